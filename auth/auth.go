@@ -1,12 +1,33 @@
 package auth
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/goat-project/goat-os/constants"
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
 	"github.com/spf13/viper"
 )
 
+// microversion of the placement API which supports resource provider traits
+const placementMicroversion = "1.6"
+
+// microversion of the shared file systems API which returns the user who created the share or the snapshot
+const sharedFileSystemMicroversion = "2.17"
+
+// microversion of the block storage API which returns the user who created the snapshot or the backup
+const blockStorageMicroversion = "3.56"
+
+// microversion of the compute API which returns servers with embedded flavor details
+const embeddedFlavorMicroversion = "2.47"
+
+// default reseller prefix of object storage accounts
+const defaultResellerPrefix = "AUTH_"
+
+// service type of the accelerator (Cyborg) service
+const acceleratorType = "accelerator"
+
 // OpenstackClient logs in to an OpenStack cloud found at the identity endpoint specified by the options,
 // acquires a token, and returns a Provider Client instance that's ready to operate.
 func OpenstackClient(opts gophercloud.AuthOptions) (*gophercloud.ProviderClient, error) {
@@ -28,14 +49,47 @@ func CreateComputeV2ServiceClient(client *gophercloud.ProviderClient) (*gophercl
 	return openstack.NewComputeV2(client, endpointOptions())
 }
 
+// CreateComputeV2EmbeddedFlavorServiceClient creates a ServiceClient that may be used with the v2 compute package.
+// Servers listed by the client contain details of their flavors, which are available even for deleted flavors.
+func CreateComputeV2EmbeddedFlavorServiceClient(client *gophercloud.ProviderClient) (*gophercloud.ServiceClient,
+	error) {
+	sc, err := CreateComputeV2ServiceClient(client)
+	if err != nil {
+		return nil, err
+	}
+
+	sc.Microversion = embeddedFlavorMicroversion
+
+	return sc, nil
+}
+
+// CreateNetworkV2ServiceClient creates a ServiceClient that may be used with the v2 networking package.
+func CreateNetworkV2ServiceClient(client *gophercloud.ProviderClient) (*gophercloud.ServiceClient, error) {
+	return openstack.NewNetworkV2(client, endpointOptions())
+}
+
 // CreateSharedFileSystemV2ServiceClient creates a ServiceClient that may be used with the v2 sharedFileSystem package.
 func CreateSharedFileSystemV2ServiceClient(client *gophercloud.ProviderClient) (*gophercloud.ServiceClient, error) {
-	return openstack.NewSharedFileSystemV2(client, endpointOptions())
+	sc, err := openstack.NewSharedFileSystemV2(client, endpointOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	sc.Microversion = sharedFileSystemMicroversion
+
+	return sc, nil
 }
 
 // CreateNewBlockStorageV3ServiceClient creates a ServiceClient that may be used with the v3 blockStorage package.
 func CreateNewBlockStorageV3ServiceClient(client *gophercloud.ProviderClient) (*gophercloud.ServiceClient, error) {
-	return openstack.NewBlockStorageV3(client, endpointOptions())
+	sc, err := openstack.NewBlockStorageV3(client, endpointOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	sc.Microversion = blockStorageMicroversion
+
+	return sc, nil
 }
 
 // CreateNewObjectStorageV1ServiceClient creates a ServiceClient that may be used with the v1 objectStorage package.
@@ -43,6 +97,87 @@ func CreateNewObjectStorageV1ServiceClient(client *gophercloud.ProviderClient) (
 	return openstack.NewObjectStorageV1(client, endpointOptions())
 }
 
+// CreateObjectStorageV1ProjectServiceClient creates a ServiceClient that may be used with the v1 objectStorage
+// package to access the account of the project. The account in the object storage endpoint of the scoped project
+// is replaced by the account of the project, access to accounts of other projects requires reseller admin role.
+func CreateObjectStorageV1ProjectServiceClient(client *gophercloud.ProviderClient,
+	projectID string) (*gophercloud.ServiceClient, error) {
+	sc, err := CreateNewObjectStorageV1ServiceClient(client)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := viper.GetString(constants.CfgSwiftResellerPrefix)
+	if prefix == "" {
+		prefix = defaultResellerPrefix
+	}
+	endpoint := strings.TrimSuffix(sc.Endpoint, "/")
+
+	i := strings.LastIndex(endpoint, "/")
+	if i < 0 || !strings.HasPrefix(endpoint[i+1:], prefix) {
+		return nil, fmt.Errorf("object storage endpoint %s does not end with account with prefix %s",
+			sc.Endpoint, prefix)
+	}
+
+	sc.Endpoint = endpoint[:i+1] + prefix + projectID + "/"
+
+	return sc, nil
+}
+
+// CreatePlacementV1ServiceClient creates a ServiceClient that may be used with the v1 placement package.
+func CreatePlacementV1ServiceClient(client *gophercloud.ProviderClient) (*gophercloud.ServiceClient, error) {
+	sc, err := openstack.NewPlacementV1(client, endpointOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	sc.Microversion = placementMicroversion
+
+	return sc, nil
+}
+
+// CreateBareMetalV1ServiceClient creates a ServiceClient that may be used with the v1 bare metal package.
+func CreateBareMetalV1ServiceClient(client *gophercloud.ProviderClient) (*gophercloud.ServiceClient, error) {
+	return openstack.NewBareMetalV1(client, endpointOptions())
+}
+
+// CreateContainerInfraV1ServiceClient creates a ServiceClient that may be used with the v1 container infra
+// (Magnum) package.
+func CreateContainerInfraV1ServiceClient(client *gophercloud.ProviderClient) (*gophercloud.ServiceClient, error) {
+	return openstack.NewContainerInfraV1(client, endpointOptions())
+}
+
+// CreateLoadBalancerV2ServiceClient creates a ServiceClient that may be used with the v2 load balancer
+// (Octavia) package.
+func CreateLoadBalancerV2ServiceClient(client *gophercloud.ProviderClient) (*gophercloud.ServiceClient, error) {
+	return openstack.NewLoadBalancerV2(client, endpointOptions())
+}
+
+// CreateAcceleratorV2ServiceClient creates a ServiceClient that may be used to access the v2 accelerator
+// (Cyborg) service.
+func CreateAcceleratorV2ServiceClient(client *gophercloud.ProviderClient) (*gophercloud.ServiceClient, error) {
+	eo := endpointOptions()
+	eo.ApplyDefaults(acceleratorType)
+
+	url, err := client.EndpointLocator(eo)
+	if err != nil {
+		return nil, err
+	}
+
+	// the catalog usually contains the versioned endpoint
+	resourceBase := url
+	if !strings.HasSuffix(strings.TrimSuffix(url, "/"), "/v2") {
+		resourceBase = gophercloud.NormalizeURL(url) + "v2/"
+	}
+
+	return &gophercloud.ServiceClient{
+		ProviderClient: client,
+		Endpoint:       url,
+		ResourceBase:   gophercloud.NormalizeURL(resourceBase),
+		Type:           acceleratorType,
+	}, nil
+}
+
 func endpointOptions() gophercloud.EndpointOpts {
 	return gophercloud.EndpointOpts{
 		Type:         viper.GetString(constants.CfgEndpointType),
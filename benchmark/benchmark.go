@@ -0,0 +1,196 @@
+package benchmark
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/reader"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/aggregates"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/spf13/viper"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// default settings
+const (
+	defaultType = "HEPSPEC"
+	defaultKey  = "goat:benchmark"
+)
+
+// Benchmark of a server - type of the benchmark and its value per CPU core.
+type Benchmark struct {
+	Type  string
+	Value float64
+}
+
+// Mapper maps servers to benchmarks. A benchmark is taken from the first of the following sources which
+// has one - flavor extra spec, table of hosts, metadata of host aggregates, table of flavors and default.
+// Values in extra specs and aggregate metadata are either a number or <type>:<number>.
+type Mapper struct {
+	reader        reader.Reader
+	benchmarkType string
+	key           string
+	hosts         map[string]float64
+	flavors       map[string]float64
+	defaultValue  float64
+
+	once       sync.Once
+	aggregates map[string]Benchmark
+}
+
+// CreateMapper creates Mapper according to configuration. The reader is used to read host aggregates.
+func CreateMapper(r *reader.Reader) *Mapper {
+	if r == nil {
+		log.WithFields(log.Fields{}).Error("error create benchmark mapper when reader is nil")
+		return nil
+	}
+
+	m := &Mapper{
+		reader:        *r,
+		benchmarkType: Type(),
+		key:           stringOrDefault(constants.CfgBenchmarkKey, defaultKey),
+		defaultValue:  viper.GetFloat64(constants.CfgBenchmarkDefault),
+	}
+
+	if err := viper.UnmarshalKey(constants.CfgBenchmarkHosts, &m.hosts); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error read benchmark hosts")
+	}
+
+	if err := viper.UnmarshalKey(constants.CfgBenchmarkFlavors, &m.flavors); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error read benchmark flavors")
+	}
+
+	return m
+}
+
+// Map returns benchmark of the server running on the host with the flavor and its extra specs. The second
+// value is false when the server does not have any benchmark.
+func (m *Mapper) Map(host, flavor string, extraSpecs map[string]string) (Benchmark, bool) {
+	if m == nil {
+		return Benchmark{}, false
+	}
+
+	if value, ok := extraSpecs[m.key]; ok {
+		if b, ok := m.parse(value); ok {
+			return b, true
+		}
+	}
+
+	if value, ok := m.hosts[host]; ok {
+		return Benchmark{Type: m.benchmarkType, Value: value}, true
+	}
+
+	if host != "" {
+		if b, ok := m.aggregateBenchmarks()[host]; ok {
+			return b, true
+		}
+	}
+
+	if value, ok := m.flavors[flavor]; ok {
+		return Benchmark{Type: m.benchmarkType, Value: value}, true
+	}
+
+	if m.defaultValue > 0 {
+		return Benchmark{Type: m.benchmarkType, Value: m.defaultValue}, true
+	}
+
+	return Benchmark{}, false
+}
+
+// aggregateBenchmarks returns benchmarks of hosts from metadata of their aggregates, the aggregates are read
+// only once.
+func (m *Mapper) aggregateBenchmarks() map[string]Benchmark {
+	m.once.Do(func() {
+		m.aggregates = make(map[string]Benchmark)
+
+		r, err := m.reader.ListAggregates()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error list aggregates")
+			return
+		}
+
+		pages, err := r.AllPages()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error get aggregate pages")
+			return
+		}
+
+		aggs, err := aggregates.ExtractAggregates(pages)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error extract aggregates")
+			return
+		}
+
+		for _, aggregate := range aggs {
+			b, ok := m.parse(aggregate.Metadata[m.key])
+			if !ok {
+				continue
+			}
+
+			for _, host := range aggregate.Hosts {
+				m.aggregates[host] = b
+			}
+		}
+	})
+
+	return m.aggregates
+}
+
+// parse parses benchmark given as a number or <type>:<number>.
+func (m *Mapper) parse(value string) (Benchmark, bool) {
+	b := Benchmark{Type: m.benchmarkType}
+
+	if i := strings.LastIndex(value, ":"); i >= 0 {
+		b.Type, value = value[:i], value[i+1:]
+	}
+
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || v <= 0 {
+		return Benchmark{}, false
+	}
+
+	b.Value = v
+
+	return b, true
+}
+
+// Hosts returns compute hosts of servers on the page by server ID. The hosts are visible only
+// to administrators.
+func Hosts(page pagination.Page) map[string]string {
+	var s []struct {
+		ID   string `json:"id"`
+		Host string `json:"OS-EXT-SRV-ATTR:host"`
+	}
+
+	hosts := make(map[string]string)
+
+	if err := servers.ExtractServersInto(page, &s); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error extract server hosts")
+		return hosts
+	}
+
+	for _, server := range s {
+		hosts[server.ID] = server.Host
+	}
+
+	return hosts
+}
+
+// Type returns configured type of benchmarks, which is used for benchmarks without a type.
+func Type() string {
+	return stringOrDefault(constants.CfgBenchmarkType, defaultType)
+}
+
+func stringOrDefault(key, defaultValue string) string {
+	if value := viper.GetString(key); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
@@ -0,0 +1,109 @@
+package benchmark
+
+import (
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/reader"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"github.com/spf13/viper"
+)
+
+var _ = ginkgo.Describe("Benchmark Mapper tests", func() {
+	var mapper *Mapper
+
+	ginkgo.JustBeforeEach(func() {
+		mapper = CreateMapper(reader.CreateReader(&gophercloud.ServiceClient{}))
+		mapper.once.Do(func() { // do not read aggregates from OpenStack
+			mapper.aggregates = map[string]Benchmark{"aggregate-host": {Type: "HS23", Value: 12}}
+		})
+	})
+
+	ginkgo.AfterEach(func() {
+		viper.Reset()
+	})
+
+	ginkgo.Context("when nothing is configured", func() {
+		ginkgo.It("should not map the server to any benchmark", func() {
+			_, ok := mapper.Map("host", "flavor", nil)
+			gomega.Expect(ok).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should map the server by the default extra spec with the default type", func() {
+			gomega.Expect(mapped(mapper, "host", "flavor", map[string]string{"goat:benchmark": "10.5"})).To(
+				gomega.Equal(&Benchmark{Type: "HEPSPEC", Value: 10.5}))
+		})
+
+		ginkgo.It("should map the server by the aggregate of its host", func() {
+			gomega.Expect(mapped(mapper, "aggregate-host", "flavor", nil)).To(
+				gomega.Equal(&Benchmark{Type: "HS23", Value: 12}))
+		})
+	})
+
+	ginkgo.Context("when all sources are configured", func() {
+		ginkgo.BeforeEach(func() {
+			viper.Set(constants.CfgBenchmarkType, "HS23")
+			viper.Set(constants.CfgBenchmarkKey, "benchmark")
+			viper.Set(constants.CfgBenchmarkHosts, map[string]float64{"host": 8, "aggregate-host": 9})
+			viper.Set(constants.CfgBenchmarkFlavors, map[string]float64{"flavor": 7})
+			viper.Set(constants.CfgBenchmarkDefault, 5)
+		})
+
+		ginkgo.It("should prefer the extra spec", func() {
+			gomega.Expect(mapped(mapper, "host", "flavor", map[string]string{"benchmark": "HEPSPEC:11"})).To(
+				gomega.Equal(&Benchmark{Type: "HEPSPEC", Value: 11}))
+		})
+
+		ginkgo.It("should ignore invalid extra spec", func() {
+			gomega.Expect(mapped(mapper, "host", "flavor", map[string]string{"benchmark": "fast"})).To(
+				gomega.Equal(&Benchmark{Type: "HS23", Value: 8}))
+		})
+
+		ginkgo.It("should prefer the host table to aggregates", func() {
+			gomega.Expect(mapped(mapper, "aggregate-host", "flavor", nil)).To(
+				gomega.Equal(&Benchmark{Type: "HS23", Value: 9}))
+		})
+
+		ginkgo.It("should map unknown host by the flavor", func() {
+			gomega.Expect(mapped(mapper, "", "flavor", nil)).To(gomega.Equal(&Benchmark{Type: "HS23", Value: 7}))
+		})
+
+		ginkgo.It("should map unknown host and flavor to the default", func() {
+			gomega.Expect(mapped(mapper, "", "other", nil)).To(gomega.Equal(&Benchmark{Type: "HS23", Value: 5}))
+		})
+	})
+
+	ginkgo.Context("when the mapper is nil", func() {
+		ginkgo.It("should not map the server to any benchmark", func() {
+			_, ok := (*Mapper)(nil).Map("host", "flavor", nil)
+			gomega.Expect(ok).To(gomega.BeFalse())
+		})
+	})
+})
+
+var _ = ginkgo.Describe("Benchmark Hosts tests", func() {
+	ginkgo.It("should return hosts of servers by their ID", func() {
+		page := servers.ServerPage{LinkedPageBase: pagination.LinkedPageBase{PageResult: pagination.PageResult{
+			Result: gophercloud.Result{Body: map[string]interface{}{"servers": []interface{}{
+				map[string]interface{}{"id": "1", "OS-EXT-SRV-ATTR:host": "compute-01"},
+				map[string]interface{}{"id": "2"},
+			}}},
+		}}}
+
+		gomega.Expect(Hosts(page)).To(gomega.Equal(map[string]string{"1": "compute-01", "2": ""}))
+	})
+})
+
+// mapped returns the benchmark of the server or nil when the server does not have any.
+func mapped(m *Mapper, host, flavor string, extraSpecs map[string]string) *Benchmark {
+	if b, ok := m.Map(host, flavor, extraSpecs); ok {
+		return &b
+	}
+
+	return nil
+}
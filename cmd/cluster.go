@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goat-project/goat-os/auth"
+	"github.com/goat-project/goat-os/reader"
+
+	"github.com/goat-project/goat-os/client"
+	"github.com/goat-project/goat-os/config"
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/filter"
+	"github.com/goat-project/goat-os/logger"
+	"github.com/goat-project/goat-os/preparer"
+	"github.com/goat-project/goat-os/processor"
+	"github.com/goat-project/goat-os/resource/cluster"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/time/rate"
+)
+
+var clusterFlags = []string{constants.CfgClusterSiteName, constants.CfgClusterCloudType,
+	constants.CfgClusterCloudComputeService}
+
+var clusterDescription = map[string]string{
+	constants.CfgClusterSiteName:  "site name [CLUSTER_SITE_NAME] (defaults to site-name)",
+	constants.CfgClusterCloudType: "cloud type [CLUSTER_CLOUD_TYPE] (defaults to cloud-type)",
+	constants.CfgClusterCloudComputeService: "cloud compute service [CLUSTER_CLOUD_COMPUTE_SERVICE] " +
+		"(defaults to cloud-compute-service)",
+}
+
+var clusterShorthand = map[string]string{}
+
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Extract kubernetes cluster data",
+	Long: "The accounting client is a command-line tool that connects to a cloud, " +
+		"extracts data about Magnum clusters and servers of their nodes, filters them accordingly and " +
+		"then sends them to a server for further processing.",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger.Init()
+
+		if viper.GetBool("debug") {
+			log.WithFields(log.Fields{"version": version}).Debug("goat-os version")
+			logFlags(clusterFlags)
+		}
+
+		validate(config.Cluster)
+
+		writeLimiter := rate.NewLimiter(rate.Every(time.Second/time.Duration(requestsPerSecond)), requestsPerSecond)
+
+		var wg sync.WaitGroup
+
+		wg.Add(1)
+		go accountCluster(writeLimiter, &wg)
+		wg.Wait()
+	},
+}
+
+func initCluster() {
+	goatOsCmd.AddCommand(clusterCmd)
+
+	createFlags(clusterCmd, clusterFlags, clusterDescription, clusterShorthand)
+	bindFlags(*clusterCmd, clusterFlags)
+}
+
+func accountCluster(writeLimiter *rate.Limiter, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	opts := options()
+
+	osClient, err := auth.OpenstackClient(opts)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Fatal("unable to create Openstack client")
+	}
+
+	identityClient, err := auth.CreateIdentityV3ServiceClient(osClient)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Fatal("unable to create Identity V3 service client")
+	}
+
+	prep := preparer.CreatePreparer(cluster.CreatePreparer(reader.CreateReader(identityClient), writeLimiter,
+		goatServerConnection()))
+	proc := processor.CreateProcessor(cluster.CreateProcessor(reader.CreateReader(identityClient)))
+	filt := filter.CreateFilter(cluster.CreateFilter())
+
+	c := client.Client{}
+	c.Run(proc, filt, prep, opts)
+}
@@ -14,8 +14,11 @@ import (
 
 	"google.golang.org/grpc"
 
+	"github.com/goat-project/goat-os/config"
 	"github.com/goat-project/goat-os/constants"
 	"github.com/goat-project/goat-os/logger"
+	"github.com/goat-project/goat-os/secret"
+	"github.com/goat-project/goat-os/util"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
@@ -26,8 +29,9 @@ const version = "1.0.0"
 const requestsPerSecond = 30
 
 var goatOsFlags = []string{constants.CfgIdentifier, constants.CfgRecordsFrom, constants.CfgRecordsTo,
-	constants.CfgRecordsForPeriod, constants.CfgGoatEndpoint, constants.CfgOpenstackIdentityEndpoint,
-	constants.CfgUsername, constants.CfgUserID, constants.CfgPassword,
+	constants.CfgRecordsForPeriod, constants.CfgGlobalSiteName, constants.CfgGlobalCloudType,
+	constants.CfgGlobalCloudComputeService, constants.CfgUserIdentity, constants.CfgGoatEndpoint,
+	constants.CfgOpenstackIdentityEndpoint, constants.CfgUsername, constants.CfgUserID, constants.CfgPassword,
 	constants.CfgPasscode, constants.CfgDomainID, constants.CfgDomainName, constants.CfgTenantID,
 	constants.CfgTenantName, constants.CfgAllowReauth, constants.CfgTokenID, constants.CfgScopeProjectID,
 	constants.CfgScopeProjectName, constants.CfgScopeDomainID, constants.CfgScopeDomainName, constants.CfgScopeSystem,
@@ -35,6 +39,11 @@ var goatOsFlags = []string{constants.CfgIdentifier, constants.CfgRecordsFrom, co
 	constants.CfgEndpointType, constants.CfgEndpointName, constants.CfgEndpointRegion,
 	constants.CfgEndpointAvailability, constants.CfgDebug, constants.CfgLogPath}
 
+// configuration values which are never logged, they may refer to a secret stored in a file, an environment
+// variable or a command output
+var goatOsSecrets = []string{constants.CfgPassword, constants.CfgPasscode, constants.CfgTokenID,
+	constants.CfgAppCredentialSecret}
+
 var goatOsRequired = []string{constants.CfgIdentifier, constants.CfgGoatEndpoint,
 	constants.CfgOpenstackIdentityEndpoint}
 
@@ -44,6 +53,11 @@ var goatOsDescription = map[string]string{
 	constants.CfgRecordsTo:        "records to [TIME]",
 	constants.CfgRecordsForPeriod: "records for period [TIME PERIOD]",
 
+	constants.CfgGlobalSiteName:            "site name of all resource types [SITE_NAME]",
+	constants.CfgGlobalCloudType:           "cloud type of all resource types [CLOUD_TYPE]",
+	constants.CfgGlobalCloudComputeService: "cloud compute service of all resource types [CLOUD_COMPUTE_SERVICE]",
+	constants.CfgUserIdentity:              "user attributes used as global user name in order of preference",
+
 	constants.CfgGoatEndpoint:              "goat server [GOAT_SERVER_ENDPOINT] (required)",
 	constants.CfgOpenstackIdentityEndpoint: "Openstack identity endpoint [OS_IDENTITY_ENDPOINT] (required)",
 
@@ -100,11 +114,13 @@ var goatOsCmd = &cobra.Command{
 			logFlags(append(vmFlags, append(networkFlags, storageFlags...)...))
 		}
 
-		err := checkRequired(append(goatOsRequired, append(vmRequired, append(networkRequired, storageRequired...)...)...))
+		err := checkRequired(goatOsRequired)
 		if err != nil {
 			log.WithFields(log.Fields{"flag": err}).Fatal("required flag not set")
 		}
 
+		validate(config.VM, config.Network, config.Storage, config.GPU)
+
 		writeLimiter := rate.NewLimiter(rate.Every(time.Second/time.Duration(requestsPerSecond)), requestsPerSecond)
 
 		var wg sync.WaitGroup
@@ -135,6 +151,9 @@ func Initialize() {
 	initNetwork()
 	initStorage()
 	initGPU()
+	initCluster()
+	initLoadBalancer()
+	initQuota()
 }
 
 func initGoatOs() {
@@ -161,6 +180,10 @@ func initConfig() {
 	if err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("error config file")
 	}
+
+	if err = secret.ResolveAll(viper.GetViper(), goatOsSecrets); err != nil {
+		log.WithFields(log.Fields{"error": err}).Fatal("unable to resolve secret")
+	}
 }
 
 func createFlags(command *cobra.Command, flags []string, descriptions map[string]string, shorthands map[string]string) {
@@ -198,7 +221,12 @@ func lastString(ss []string) string {
 
 func logFlags(flags []string) {
 	for _, flag := range append(goatOsFlags, flags...) {
-		log.WithFields(log.Fields{"flag": flag, "value": viper.Get(flag)}).Debug("flag initialized")
+		value := viper.Get(flag)
+		if util.Contains(goatOsSecrets, flag) && viper.GetString(flag) != "" {
+			value = secret.Mask
+		}
+
+		log.WithFields(log.Fields{"flag": flag, "value": value}).Debug("flag initialized")
 	}
 }
 
@@ -212,6 +240,13 @@ func checkRequired(required []string) error {
 	return nil
 }
 
+// validate terminates the program when the configuration of the resource types is incomplete or inconsistent.
+func validate(resourceTypes ...string) {
+	if err := config.Validate(resourceTypes...); err != nil {
+		log.WithFields(log.Fields{"error": err}).Fatal("invalid configuration")
+	}
+}
+
 func goatServerConnection() *grpc.ClientConn {
 	conn, err := grpc.Dial(viper.GetString(constants.CfgGoatEndpoint), grpc.WithTransportCredentials(
 		insecure.NewCredentials()))
@@ -6,6 +6,7 @@ import (
 
 	"github.com/goat-project/goat-os/auth"
 	"github.com/goat-project/goat-os/client"
+	"github.com/goat-project/goat-os/config"
 	"github.com/goat-project/goat-os/constants"
 	"github.com/goat-project/goat-os/filter"
 	"github.com/goat-project/goat-os/logger"
@@ -19,12 +20,15 @@ import (
 	"golang.org/x/time/rate"
 )
 
-var gpuFlags []string
-
-var gpuRequired = []string{constants.CfgGPUSiteName}
+var gpuFlags = []string{constants.CfgGPUSiteName, constants.CfgGPUExtraSpecs, constants.CfgGPUFlavorNames,
+	constants.CfgGPUDiscovery, constants.CfgGPUResourceClasses}
 
 var gpuDescription = map[string]string{
-	constants.CfgGPUSiteName: "site name [SITE]",
+	constants.CfgGPUSiteName:        "site name [GPU_SITE_NAME] (defaults to site-name)",
+	constants.CfgGPUExtraSpecs:      "flavor extra specs marking gpu flavors [EXTRA_SPECS]",
+	constants.CfgGPUFlavorNames:     "regular expressions matching names of gpu flavors [FLAVOR_NAMES]",
+	constants.CfgGPUDiscovery:       "sources of gpu discovery - flavor, placement, cyborg [DISCOVERY]",
+	constants.CfgGPUResourceClasses: "placement resource classes counted as gpus [RESOURCE_CLASSES]",
 }
 
 var gpuShorthand = map[string]string{}
@@ -43,16 +47,7 @@ var gpuCmd = &cobra.Command{
 			logFlags(gpuFlags)
 		}
 
-		err := checkRequired(gpuRequired)
-		if err != nil {
-			log.WithFields(log.Fields{"flag": err}).Fatal("required flag not set")
-		}
-
-		accounted := viper.GetStringSlice(constants.CfgAccounted)
-		if len(accounted) < 1 {
-			log.WithField("error", "no accounted for gpu are set in configuration").Error("error account gpu")
-			return
-		}
+		validate(config.GPU)
 
 		writeLimiter := rate.NewLimiter(rate.Every(time.Second/time.Duration(requestsPerSecond)), requestsPerSecond)
 
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goat-project/goat-os/auth"
+	"github.com/goat-project/goat-os/reader"
+
+	"github.com/goat-project/goat-os/client"
+	"github.com/goat-project/goat-os/config"
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/filter"
+	"github.com/goat-project/goat-os/logger"
+	"github.com/goat-project/goat-os/preparer"
+	"github.com/goat-project/goat-os/processor"
+	"github.com/goat-project/goat-os/resource/loadbalancer"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/time/rate"
+)
+
+var loadBalancerFlags = []string{constants.CfgLoadBalancerSiteName, constants.CfgLoadBalancerCloudType,
+	constants.CfgLoadBalancerCloudComputeService}
+
+var loadBalancerDescription = map[string]string{
+	constants.CfgLoadBalancerSiteName:  "site name [LOADBALANCER_SITE_NAME] (defaults to site-name)",
+	constants.CfgLoadBalancerCloudType: "cloud type [LOADBALANCER_CLOUD_TYPE] (defaults to cloud-type)",
+	constants.CfgLoadBalancerCloudComputeService: "cloud compute service [LOADBALANCER_CLOUD_COMPUTE_SERVICE] " +
+		"(defaults to cloud-compute-service)",
+}
+
+var loadBalancerShorthand = map[string]string{}
+
+var loadBalancerCmd = &cobra.Command{
+	Use:   "loadbalancer",
+	Short: "Extract load balancer data",
+	Long: "The accounting client is a command-line tool that connects to a cloud, " +
+		"extracts data about load balancers, filters them accordingly and " +
+		"then sends them to a server for further processing.",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger.Init()
+
+		if viper.GetBool("debug") {
+			log.WithFields(log.Fields{"version": version}).Debug("goat-os version")
+			logFlags(loadBalancerFlags)
+		}
+
+		validate(config.LoadBalancer)
+
+		writeLimiter := rate.NewLimiter(rate.Every(time.Second/time.Duration(requestsPerSecond)), requestsPerSecond)
+
+		var wg sync.WaitGroup
+
+		wg.Add(1)
+		go accountLoadBalancer(writeLimiter, &wg)
+		wg.Wait()
+	},
+}
+
+func initLoadBalancer() {
+	goatOsCmd.AddCommand(loadBalancerCmd)
+
+	createFlags(loadBalancerCmd, loadBalancerFlags, loadBalancerDescription, loadBalancerShorthand)
+	bindFlags(*loadBalancerCmd, loadBalancerFlags)
+}
+
+func accountLoadBalancer(writeLimiter *rate.Limiter, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	opts := options()
+
+	osClient, err := auth.OpenstackClient(opts)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Fatal("unable to create Openstack client")
+	}
+
+	identityClient, err := auth.CreateIdentityV3ServiceClient(osClient)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Fatal("unable to create Identity V3 service client")
+	}
+
+	prep := preparer.CreatePreparer(loadbalancer.CreatePreparer(reader.CreateReader(identityClient), writeLimiter,
+		goatServerConnection()))
+	proc := processor.CreateProcessor(loadbalancer.CreateProcessor(reader.CreateReader(identityClient)))
+	filt := filter.CreateFilter(loadbalancer.CreateFilter())
+
+	c := client.Client{}
+	c.Run(proc, filt, prep, opts)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/goat-project/goat-os/reader"
 
 	"github.com/goat-project/goat-os/client"
+	"github.com/goat-project/goat-os/config"
 	"github.com/goat-project/goat-os/constants"
 	"github.com/goat-project/goat-os/filter"
 	"github.com/goat-project/goat-os/logger"
@@ -21,14 +22,17 @@ import (
 )
 
 var networkFlags = []string{constants.CfgNetworkSiteName, constants.CfgNetworkCloudType,
-	constants.CfgNetworkCloudComputeService}
-
-var networkRequired = []string{constants.CfgNetworkSiteName, constants.CfgNetworkCloudType}
+	constants.CfgNetworkCloudComputeService, constants.CfgNetworkPublicNetworks, constants.CfgNetworkIPCount,
+	constants.CfgNetworkStatePath}
 
 var networkDescription = map[string]string{
-	constants.CfgNetworkSiteName:            "site name [NETWORK_SITE_NAME] (required)",
-	constants.CfgNetworkCloudType:           "cloud type [NETWORK_CLOUD_TYPE] (required)",
-	constants.CfgNetworkCloudComputeService: "cloud compute service [NETWORK_CLOUD_COMPUTE_SERVICE]",
+	constants.CfgNetworkSiteName:  "site name [NETWORK_SITE_NAME] (defaults to site-name)",
+	constants.CfgNetworkCloudType: "cloud type [NETWORK_CLOUD_TYPE] (defaults to cloud-type)",
+	constants.CfgNetworkCloudComputeService: "cloud compute service [NETWORK_CLOUD_COMPUTE_SERVICE] " +
+		"(defaults to cloud-compute-service)",
+	constants.CfgNetworkPublicNetworks: "ids or names of networks with public IPs (defaults to external networks)",
+	constants.CfgNetworkIPCount:        "counting of IPs in the period - current, peak, average (defaults to peak)",
+	constants.CfgNetworkStatePath:      "path to file with history of public IPs [NETWORK_STATE_PATH]",
 }
 
 var networkShorthand = map[string]string{}
@@ -47,10 +51,7 @@ var networkCmd = &cobra.Command{
 			logFlags(networkFlags)
 		}
 
-		err := checkRequired(networkRequired)
-		if err != nil {
-			log.WithFields(log.Fields{"flag": err}).Fatal("required flag not set")
-		}
+		validate(config.Network)
 
 		writeLimiter := rate.NewLimiter(rate.Every(time.Second/time.Duration(requestsPerSecond)), requestsPerSecond)
 
@@ -85,7 +86,8 @@ func accountNetwork(writeLimiter *rate.Limiter, wg *sync.WaitGroup) {
 
 	proc := processor.CreateProcessor(network.CreateProcessor(reader.CreateReader(identityClient)))
 	filt := filter.CreateFilter(network.CreateFilter())
-	prep := preparer.CreatePreparer(network.CreatePreparer(writeLimiter, goatServerConnection()))
+	prep := preparer.CreatePreparer(network.CreatePreparer(reader.CreateReader(identityClient), writeLimiter,
+		goatServerConnection()))
 
 	c := client.Client{}
 
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goat-project/goat-os/auth"
+	"github.com/goat-project/goat-os/reader"
+
+	"github.com/goat-project/goat-os/client"
+	"github.com/goat-project/goat-os/config"
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/filter"
+	"github.com/goat-project/goat-os/logger"
+	"github.com/goat-project/goat-os/preparer"
+	"github.com/goat-project/goat-os/processor"
+	"github.com/goat-project/goat-os/resource/quota"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/time/rate"
+)
+
+var quotaFlags = []string{constants.CfgQuotaSiteName, constants.CfgQuotaCloudType,
+	constants.CfgQuotaCloudComputeService, constants.CfgQuotaPeriod}
+
+var quotaDescription = map[string]string{
+	constants.CfgQuotaSiteName:  "site name [QUOTA_SITE_NAME] (defaults to site-name)",
+	constants.CfgQuotaCloudType: "cloud type [QUOTA_CLOUD_TYPE] (defaults to cloud-type)",
+	constants.CfgQuotaCloudComputeService: "cloud compute service [QUOTA_CLOUD_COMPUTE_SERVICE] " +
+		"(defaults to cloud-compute-service)",
+	constants.CfgQuotaPeriod: "period before reading of quotas they are accounted for [QUOTA_PERIOD] " +
+		"(defaults to records-for-period or 1h)",
+}
+
+var quotaShorthand = map[string]string{}
+
+var quotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "Extract quota data",
+	Long: "The accounting client is a command-line tool that connects to a cloud, " +
+		"extracts quotas of projects and sends capacity reserved by them for the period " +
+		"before their reading to a server for further processing.",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger.Init()
+
+		if viper.GetBool("debug") {
+			log.WithFields(log.Fields{"version": version}).Debug("goat-os version")
+			logFlags(quotaFlags)
+		}
+
+		validate(config.Quota)
+
+		writeLimiter := rate.NewLimiter(rate.Every(time.Second/time.Duration(requestsPerSecond)), requestsPerSecond)
+
+		var wg sync.WaitGroup
+
+		wg.Add(3)
+		go accountQuota(writeLimiter, quota.KindCompute, &wg)
+		go accountQuota(writeLimiter, quota.KindStorage, &wg)
+		go accountQuota(writeLimiter, quota.KindNetwork, &wg)
+		wg.Wait()
+	},
+}
+
+func initQuota() {
+	goatOsCmd.AddCommand(quotaCmd)
+
+	createFlags(quotaCmd, quotaFlags, quotaDescription, quotaShorthand)
+	bindFlags(*quotaCmd, quotaFlags)
+}
+
+func accountQuota(writeLimiter *rate.Limiter, kind string, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	opts := options()
+
+	osClient, err := auth.OpenstackClient(opts)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Fatal("unable to create Openstack client")
+	}
+
+	identityClient, err := auth.CreateIdentityV3ServiceClient(osClient)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Fatal("unable to create Identity V3 service client")
+	}
+
+	prep := preparer.CreatePreparer(quota.CreatePreparer(reader.CreateReader(identityClient), writeLimiter,
+		goatServerConnection(), kind))
+	proc := processor.CreateProcessor(quota.CreateProcessor(reader.CreateReader(identityClient), kind))
+	filt := filter.CreateFilter(quota.CreateFilter())
+
+	c := client.Client{}
+	c.Run(proc, filt, prep, opts)
+}
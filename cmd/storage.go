@@ -6,6 +6,7 @@ import (
 
 	"github.com/goat-project/goat-os/auth"
 	"github.com/goat-project/goat-os/client"
+	"github.com/goat-project/goat-os/config"
 	"github.com/goat-project/goat-os/constants"
 	"github.com/goat-project/goat-os/filter"
 	"github.com/goat-project/goat-os/logger"
@@ -19,13 +20,16 @@ import (
 	"golang.org/x/time/rate"
 )
 
-var storageFlags = []string{constants.CfgSite, constants.CfgAccounted}
-
-var storageRequired []string
+var storageFlags = []string{constants.CfgStorageSiteName, constants.CfgSite, constants.CfgAccounted,
+	constants.CfgShareSnapshotClass, constants.CfgShareReplicaClass, constants.CfgSwiftResellerPrefix}
 
 var storageDescription = map[string]string{
-	constants.CfgSite:      "site [SITE]",
-	constants.CfgAccounted: "accounted [storages]",
+	constants.CfgStorageSiteName:     "site name [STORAGE_SITE_NAME] (defaults to site-name)",
+	constants.CfgSite:                "site [SITE] (deprecated, use site-name)",
+	constants.CfgAccounted:           "accounted [storages]",
+	constants.CfgShareSnapshotClass:  "storage class of share snapshots",
+	constants.CfgShareReplicaClass:   "storage class of share replicas",
+	constants.CfgSwiftResellerPrefix: "reseller prefix of swift accounts (defaults to AUTH_)",
 }
 
 var storageShorthand = map[string]string{}
@@ -44,10 +48,7 @@ var storageCmd = &cobra.Command{
 			logFlags(storageFlags)
 		}
 
-		err := checkRequired(storageRequired)
-		if err != nil {
-			log.WithFields(log.Fields{"flag": err}).Fatal("required flag not set")
-		}
+		validate(config.Storage)
 
 		accounted := viper.GetStringSlice(constants.CfgAccounted)
 		if len(accounted) < 1 {
@@ -8,6 +8,7 @@ import (
 	"github.com/goat-project/goat-os/reader"
 
 	"github.com/goat-project/goat-os/client"
+	"github.com/goat-project/goat-os/config"
 	"github.com/goat-project/goat-os/constants"
 	"github.com/goat-project/goat-os/filter"
 	"github.com/goat-project/goat-os/logger"
@@ -20,14 +21,16 @@ import (
 	"golang.org/x/time/rate"
 )
 
-var vmFlags = []string{constants.CfgSiteName, constants.CfgCloudType, constants.CfgCloudComputeService}
-
-var vmRequired = []string{constants.CfgSiteName, constants.CfgCloudType}
+var vmFlags = []string{constants.CfgSiteName, constants.CfgCloudType, constants.CfgCloudComputeService,
+	constants.CfgVMStatePath, constants.CfgVMExcludeAmphorae}
 
 var vmDescription = map[string]string{
-	constants.CfgSiteName:            "site name [VM_SITE_NAME] (required)",
-	constants.CfgCloudType:           "cloud type [VM_CLOUD_TYPE] (required)",
-	constants.CfgCloudComputeService: "cloud compute service [VM_CLOUD_COMPUTE_SERVICE]",
+	constants.CfgSiteName:  "site name [VM_SITE_NAME] (defaults to site-name)",
+	constants.CfgCloudType: "cloud type [VM_CLOUD_TYPE] (defaults to cloud-type)",
+	constants.CfgCloudComputeService: "cloud compute service [VM_CLOUD_COMPUTE_SERVICE] " +
+		"(defaults to cloud-compute-service)",
+	constants.CfgVMStatePath:       "path to file with history of server flavors [VM_STATE_PATH]",
+	constants.CfgVMExcludeAmphorae: "exclude amphora servers of load balancers [VM_EXCLUDE_AMPHORAE]",
 }
 
 var vmShorthand = map[string]string{}
@@ -46,10 +49,7 @@ var vmCmd = &cobra.Command{
 			logFlags(vmFlags)
 		}
 
-		err := checkRequired(vmRequired)
-		if err != nil {
-			log.WithFields(log.Fields{"flag": err}).Fatal("required flag not set")
-		}
+		validate(config.VM)
 
 		writeLimiter := rate.NewLimiter(rate.Every(time.Second/time.Duration(requestsPerSecond)), requestsPerSecond)
 
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goat-project/goat-os/constants"
+
+	"github.com/karrick/tparse/v2"
+
+	"github.com/spf13/viper"
+)
+
+// resource types with their own configuration section
+const (
+	VM      = "vm"
+	Network = "network"
+	Storage = "storage"
+	GPU     = "gpu"
+	Cluster = "cluster"
+
+	LoadBalancer = "loadbalancer"
+	Quota        = "quota"
+)
+
+// configuration keys of the resource types in order of precedence,
+// the global setting is used when none of them is set
+var (
+	siteNames = map[string][]string{
+		VM:      {constants.CfgSiteName},
+		Network: {constants.CfgNetworkSiteName},
+		Storage: {constants.CfgStorageSiteName, constants.CfgSite},
+		GPU:     {constants.CfgGPUSiteName},
+		Cluster: {constants.CfgClusterSiteName},
+
+		LoadBalancer: {constants.CfgLoadBalancerSiteName},
+		Quota:        {constants.CfgQuotaSiteName},
+	}
+
+	cloudTypes = map[string][]string{
+		VM:      {constants.CfgCloudType},
+		Network: {constants.CfgNetworkCloudType},
+		Cluster: {constants.CfgClusterCloudType},
+
+		LoadBalancer: {constants.CfgLoadBalancerCloudType},
+		Quota:        {constants.CfgQuotaCloudType},
+	}
+
+	cloudComputeServices = map[string][]string{
+		VM:      {constants.CfgCloudComputeService},
+		Network: {constants.CfgNetworkCloudComputeService},
+		Cluster: {constants.CfgClusterCloudComputeService},
+
+		LoadBalancer: {constants.CfgLoadBalancerCloudComputeService},
+		Quota:        {constants.CfgQuotaCloudComputeService},
+	}
+)
+
+// SiteName returns site name of the resource type or the global site name.
+func SiteName(resourceType string) string {
+	return value(siteNames[resourceType], constants.CfgGlobalSiteName)
+}
+
+// CloudType returns cloud type of the resource type or the global cloud type.
+func CloudType(resourceType string) string {
+	return value(cloudTypes[resourceType], constants.CfgGlobalCloudType)
+}
+
+// CloudComputeService returns cloud compute service of the resource type or the global cloud compute service.
+func CloudComputeService(resourceType string) string {
+	return value(cloudComputeServices[resourceType], constants.CfgGlobalCloudComputeService)
+}
+
+// Validate checks that the configuration of the resource types is complete and consistent.
+// It returns the first problem found.
+func Validate(resourceTypes ...string) error {
+	if err := validatePeriod(); err != nil {
+		return err
+	}
+
+	for _, resourceType := range resourceTypes {
+		// site of storage records is optional
+		if resourceType != Storage && SiteName(resourceType) == "" {
+			return fmt.Errorf("no site name for %s, set %s or %s", resourceType,
+				siteNames[resourceType][0], constants.CfgGlobalSiteName)
+		}
+
+		if keys, ok := cloudTypes[resourceType]; ok && CloudType(resourceType) == "" {
+			return fmt.Errorf("no cloud type for %s, set %s or %s", resourceType, keys[0],
+				constants.CfgGlobalCloudType)
+		}
+	}
+
+	site := viper.GetString(constants.CfgSite)
+	siteName := viper.GetString(constants.CfgStorageSiteName)
+	if site != "" && siteName != "" && site != siteName {
+		return fmt.Errorf("%s (%s) differs from deprecated %s (%s)", constants.CfgStorageSiteName, siteName,
+			constants.CfgSite, site)
+	}
+
+	return nil
+}
+
+func validatePeriod() error {
+	recordsFrom := viper.GetTime(constants.CfgRecordsFrom)
+	recordsTo := viper.GetTime(constants.CfgRecordsTo)
+	periodStr := viper.GetString(constants.CfgRecordsForPeriod)
+
+	if periodStr != "" {
+		if _, err := tparse.AddDuration(time.Time{}, periodStr); err != nil {
+			return fmt.Errorf("wrong format of %s: %s", constants.CfgRecordsForPeriod, periodStr)
+		}
+
+		if !recordsFrom.IsZero() || !recordsTo.IsZero() {
+			return fmt.Errorf("cannot set %s or %s together with %s", constants.CfgRecordsFrom,
+				constants.CfgRecordsTo, constants.CfgRecordsForPeriod)
+		}
+	}
+
+	if !recordsFrom.IsZero() && !recordsTo.IsZero() && recordsFrom.After(recordsTo) {
+		return fmt.Errorf("%s (%s) is later than %s (%s)", constants.CfgRecordsFrom, recordsFrom,
+			constants.CfgRecordsTo, recordsTo)
+	}
+
+	return nil
+}
+
+func value(keys []string, global string) string {
+	for _, key := range keys {
+		if v := viper.GetString(key); v != "" {
+			return v
+		}
+	}
+
+	return viper.GetString(global)
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"time"
+
+	"github.com/goat-project/goat-os/constants"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"github.com/spf13/viper"
+)
+
+var _ = ginkgo.Describe("Config tests", func() {
+	ginkgo.AfterEach(func() {
+		viper.Reset()
+	})
+
+	ginkgo.Describe("site name", func() {
+		ginkgo.Context("when only the global site name is set", func() {
+			ginkgo.It("should be used for every resource type", func() {
+				viper.Set(constants.CfgGlobalSiteName, "global-site")
+
+				for _, resourceType := range []string{VM, Network, Storage, GPU} {
+					gomega.Expect(SiteName(resourceType)).To(gomega.Equal("global-site"))
+				}
+			})
+		})
+
+		ginkgo.Context("when the resource type sets its own site name", func() {
+			ginkgo.It("should override the global site name", func() {
+				viper.Set(constants.CfgGlobalSiteName, "global-site")
+				viper.Set(constants.CfgGPUSiteName, "gpu-site")
+
+				gomega.Expect(SiteName(GPU)).To(gomega.Equal("gpu-site"))
+				gomega.Expect(SiteName(VM)).To(gomega.Equal("global-site"))
+			})
+		})
+
+		ginkgo.Context("when only the deprecated storage site is set", func() {
+			ginkgo.It("should be used for storage", func() {
+				viper.Set(constants.CfgSite, "storage-site")
+
+				gomega.Expect(SiteName(Storage)).To(gomega.Equal("storage-site"))
+			})
+		})
+	})
+
+	ginkgo.Describe("cloud type", func() {
+		ginkgo.It("should fall back to the global cloud type", func() {
+			viper.Set(constants.CfgGlobalCloudType, "openstack")
+			viper.Set(constants.CfgNetworkCloudType, "openstack-network")
+
+			gomega.Expect(CloudType(VM)).To(gomega.Equal("openstack"))
+			gomega.Expect(CloudType(Network)).To(gomega.Equal("openstack-network"))
+		})
+	})
+
+	ginkgo.Describe("validate", func() {
+		ginkgo.BeforeEach(func() {
+			viper.Set(constants.CfgGlobalSiteName, "site")
+			viper.Set(constants.CfgGlobalCloudType, "openstack")
+		})
+
+		ginkgo.Context("when the configuration is complete", func() {
+			ginkgo.It("should not return an error", func() {
+				gomega.Expect(Validate(VM, Network, Storage, GPU)).To(gomega.Succeed())
+			})
+		})
+
+		ginkgo.Context("when no site name is resolved", func() {
+			ginkgo.It("should return an error", func() {
+				viper.Set(constants.CfgGlobalSiteName, "")
+
+				gomega.Expect(Validate(GPU)).NotTo(gomega.Succeed())
+				gomega.Expect(Validate(Storage)).To(gomega.Succeed())
+			})
+		})
+
+		ginkgo.Context("when no cloud type is resolved", func() {
+			ginkgo.It("should return an error for types with a cloud type", func() {
+				viper.Set(constants.CfgGlobalCloudType, "")
+
+				gomega.Expect(Validate(VM)).NotTo(gomega.Succeed())
+				gomega.Expect(Validate(GPU)).To(gomega.Succeed())
+			})
+		})
+
+		ginkgo.Context("when storage site and site name differ", func() {
+			ginkgo.It("should return an error", func() {
+				viper.Set(constants.CfgSite, "old-site")
+				viper.Set(constants.CfgStorageSiteName, "new-site")
+
+				gomega.Expect(Validate(Storage)).NotTo(gomega.Succeed())
+			})
+		})
+
+		ginkgo.Context("when a period is set together with records from", func() {
+			ginkgo.It("should return an error", func() {
+				viper.Set(constants.CfgRecordsFrom, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+				viper.Set(constants.CfgRecordsForPeriod, "1mo")
+
+				gomega.Expect(Validate()).NotTo(gomega.Succeed())
+			})
+		})
+
+		ginkgo.Context("when records from is later than records to", func() {
+			ginkgo.It("should return an error", func() {
+				viper.Set(constants.CfgRecordsFrom, time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC))
+				viper.Set(constants.CfgRecordsTo, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+
+				gomega.Expect(Validate()).NotTo(gomega.Succeed())
+			})
+		})
+	})
+})
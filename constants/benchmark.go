@@ -0,0 +1,18 @@
+package constants
+
+// prefix for mapping of servers to benchmarks
+const cfgBenchmarkPrefix = "benchmark."
+
+// constants for mapping of servers to benchmarks
+const (
+	// CfgBenchmarkType represents string of type of benchmarks (e.g. HEPSPEC, HS23)
+	CfgBenchmarkType = cfgBenchmarkPrefix + "type"
+	// CfgBenchmarkKey represents string of flavor extra spec and aggregate metadata key with benchmark
+	CfgBenchmarkKey = cfgBenchmarkPrefix + "key"
+	// CfgBenchmarkHosts represents map of compute hosts to benchmarks
+	CfgBenchmarkHosts = cfgBenchmarkPrefix + "hosts"
+	// CfgBenchmarkFlavors represents map of flavor names to benchmarks
+	CfgBenchmarkFlavors = cfgBenchmarkPrefix + "flavors"
+	// CfgBenchmarkDefault represents benchmark of servers without any other benchmark
+	CfgBenchmarkDefault = cfgBenchmarkPrefix + "default"
+)
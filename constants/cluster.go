@@ -0,0 +1,14 @@
+package constants
+
+// prefix for cluster subcommands
+const cfgClusterPrefix = "cluster."
+
+// constants for cluster subcommand
+const (
+	// CfgClusterSiteName represents string of cluster site name
+	CfgClusterSiteName = cfgClusterPrefix + "site-name"
+	// CfgClusterCloudType represents string of cluster cloud type
+	CfgClusterCloudType = cfgClusterPrefix + "cloud-type"
+	// CfgClusterCloudComputeService represents string of cluster cloud compute service
+	CfgClusterCloudComputeService = cfgClusterPrefix + "cloud-compute-service"
+)
@@ -29,4 +29,10 @@ const (
 	ErrCreateProcReaderNil = "error create Processor when Reader is nil"
 
 	ErrPrepEmptyGPU = "error prepare empty GPU struct"
+
+	ErrPrepEmptyCluster = "error prepare empty cluster struct"
+
+	ErrPrepEmptyLoadBalancer = "error prepare empty load balancer struct"
+
+	ErrPrepEmptyQuota = "error prepare empty quota struct"
 )
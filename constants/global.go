@@ -12,6 +12,17 @@ const (
 	// CfgRecordsFrom represents duration which records are filtered for
 	CfgRecordsForPeriod = "records-for-period"
 
+	// CfgGlobalSiteName represents string of site name used when a resource type does not set its own
+	CfgGlobalSiteName = "site-name"
+	// CfgGlobalCloudType represents string of cloud type used when a resource type does not set its own
+	CfgGlobalCloudType = "cloud-type"
+	// CfgGlobalCloudComputeService represents string of cloud compute service used when a resource type
+	// does not set its own
+	CfgGlobalCloudComputeService = "cloud-compute-service"
+
+	// CfgUserIdentity represents array of user attributes used as global user name in order of preference
+	CfgUserIdentity = "user-identity"
+
 	// CfgGoatEndpoint represents string of goat server endpoint
 	CfgGoatEndpoint = "endpoint"
 
@@ -7,4 +7,16 @@ const cfgGPUPrefix = "gpu."
 const (
 	// CfgGPUSiteName represents string of gpu site name
 	CfgGPUSiteName = cfgGPUPrefix + "site-name"
+	// CfgGPUExtraSpecs represents array of flavor extra spec keys which mark a flavor as a gpu flavor
+	CfgGPUExtraSpecs = cfgGPUPrefix + "extra-specs"
+	// CfgGPUFlavorNames represents array of regular expressions matching names of gpu flavors
+	CfgGPUFlavorNames = cfgGPUPrefix + "flavor-names"
+	// CfgGPUPCIAliases represents array of regular expressions matching names of pci passthrough aliases of gpus
+	CfgGPUPCIAliases = cfgGPUPrefix + "pci-aliases"
+	// CfgGPUDiscovery represents array of gpu discovery methods (flavor, placement, cyborg)
+	CfgGPUDiscovery = cfgGPUPrefix + "discovery"
+	// CfgGPUResourceClasses represents array of placement resource classes of gpu devices
+	CfgGPUResourceClasses = cfgGPUPrefix + "resource-classes"
+	// CfgGPUModels represents table mapping gpu devices to their type (vendor) and model
+	CfgGPUModels = cfgGPUPrefix + "models"
 )
@@ -0,0 +1,14 @@
+package constants
+
+// prefix for load balancer subcommands
+const cfgLoadBalancerPrefix = "loadbalancer."
+
+// constants for load balancer subcommand
+const (
+	// CfgLoadBalancerSiteName represents string of load balancer site name
+	CfgLoadBalancerSiteName = cfgLoadBalancerPrefix + "site-name"
+	// CfgLoadBalancerCloudType represents string of load balancer cloud type
+	CfgLoadBalancerCloudType = cfgLoadBalancerPrefix + "cloud-type"
+	// CfgLoadBalancerCloudComputeService represents string of load balancer cloud compute service
+	CfgLoadBalancerCloudComputeService = cfgLoadBalancerPrefix + "cloud-compute-service"
+)
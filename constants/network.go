@@ -11,4 +11,10 @@ const (
 	CfgNetworkCloudType = cfgNetworkPrefix + "cloud-type"
 	// CfgNetworkCloudComputeService represents string of network cloud compute service
 	CfgNetworkCloudComputeService = cfgNetworkPrefix + "cloud-compute-service"
+	// CfgNetworkPublicNetworks represents array of ids or names of networks with public IPs
+	CfgNetworkPublicNetworks = cfgNetworkPrefix + "public-networks"
+	// CfgNetworkIPCount represents mode of counting IPs in the filtered period (current, peak, average)
+	CfgNetworkIPCount = cfgNetworkPrefix + "ip-count"
+	// CfgNetworkStatePath represents path to the file with history of public IPs stored between runs
+	CfgNetworkStatePath = cfgNetworkPrefix + "state-path"
 )
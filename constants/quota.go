@@ -0,0 +1,16 @@
+package constants
+
+// prefix for quota subcommands
+const cfgQuotaPrefix = "quota."
+
+// constants for quota subcommand
+const (
+	// CfgQuotaSiteName represents string of quota site name
+	CfgQuotaSiteName = cfgQuotaPrefix + "site-name"
+	// CfgQuotaCloudType represents string of quota cloud type
+	CfgQuotaCloudType = cfgQuotaPrefix + "cloud-type"
+	// CfgQuotaCloudComputeService represents string of quota cloud compute service
+	CfgQuotaCloudComputeService = cfgQuotaPrefix + "cloud-compute-service"
+	// CfgQuotaPeriod represents string of length of the period before the reading of quotas they are accounted for
+	CfgQuotaPeriod = cfgQuotaPrefix + "period"
+)
@@ -5,8 +5,18 @@ const cfgStoragePrefix = "storage."
 
 // constants for storage subcommand
 const (
+	// CfgStorageSiteName represents string of storage site name
+	CfgStorageSiteName = cfgStoragePrefix + "site-name"
 	// CfgSite represents string of storage site
+	//
+	// Deprecated: use CfgStorageSiteName.
 	CfgSite = cfgStoragePrefix + "site"
 	// CfgAccounted represents array of storages to be accounted
 	CfgAccounted = cfgStoragePrefix + "accounted"
+	// CfgSwiftResellerPrefix represents string of reseller prefix of swift accounts
+	CfgSwiftResellerPrefix = cfgStoragePrefix + "swift-reseller-prefix"
+	// CfgShareSnapshotClass represents string of storage class of share snapshots
+	CfgShareSnapshotClass = cfgStoragePrefix + "share-snapshot-class"
+	// CfgShareReplicaClass represents string of storage class of share replicas
+	CfgShareReplicaClass = cfgStoragePrefix + "share-replica-class"
 )
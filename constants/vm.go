@@ -11,4 +11,24 @@ const (
 	CfgCloudType = cfgVMPrefix + "cloud-type"
 	// CfgCloudComputeService represents string of virtual machine cloud compute service
 	CfgCloudComputeService = cfgVMPrefix + "cloud-compute-service"
+	// CfgVMStatePath represents path to the file with history of server flavors stored between runs
+	CfgVMStatePath = cfgVMPrefix + "state-path"
+	// CfgVMRules represents list of rules assigning site name, cloud compute service and benchmark to servers
+	// by their availability zone, host, aggregate and hypervisor type
+	CfgVMRules = cfgVMPrefix + "rules"
+	// CfgVMCloudComputeServiceTemplate represents template of cloud compute service of virtual machine records
+	// which writes the location of servers into the records
+	CfgVMCloudComputeServiceTemplate = cfgVMPrefix + "cloud-compute-service-template"
+	// CfgVMExcludeAmphorae represents bool whether amphora servers of load balancers are excluded from accounting
+	CfgVMExcludeAmphorae = cfgVMPrefix + "exclude-amphorae"
+	// CfgVMBareMetal represents bool whether servers on bare metal nodes are accounted with properties of the nodes
+	CfgVMBareMetal = cfgVMPrefix + "bare-metal.enabled"
+	// CfgVMBareMetalCloudType represents string of cloud type of servers on bare metal nodes
+	CfgVMBareMetalCloudType = cfgVMPrefix + "bare-metal.cloud-type"
+	// CfgVMBareMetalCloudComputeService represents string of cloud compute service of servers on bare metal nodes
+	CfgVMBareMetalCloudComputeService = cfgVMPrefix + "bare-metal.cloud-compute-service"
+	// CfgVMUsageCheck represents bool whether usage of servers is compared with usage computed by Nova
+	CfgVMUsageCheck = cfgVMPrefix + "usage-check.enabled"
+	// CfgVMUsageCheckTolerance represents relative difference (percent) of usages which is not reported
+	CfgVMUsageCheckTolerance = cfgVMPrefix + "usage-check.tolerance"
 )
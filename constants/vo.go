@@ -0,0 +1,25 @@
+package constants
+
+// prefix for mapping of projects to virtual organizations
+const cfgVOPrefix = "vo."
+
+// constants for mapping of projects to virtual organizations
+const (
+	// CfgVOEnabled represents bool whether projects are mapped to virtual organizations, records keep their
+	// original groups and FQANs otherwise
+	CfgVOEnabled = cfgVOPrefix + "enabled"
+	// CfgVOSource represents string of source of virtual organizations (project, static, property, tag, template)
+	CfgVOSource = cfgVOPrefix + "source"
+	// CfgVOTable represents table mapping projects to virtual organizations and roles
+	CfgVOTable = cfgVOPrefix + "table"
+	// CfgVOProperty represents string of project property with name of virtual organization
+	CfgVOProperty = cfgVOPrefix + "property"
+	// CfgVOTagPrefix represents string of prefix of project tag with name of virtual organization
+	CfgVOTagPrefix = cfgVOPrefix + "tag-prefix"
+	// CfgVOTemplate represents string of template of virtual organization name
+	CfgVOTemplate = cfgVOPrefix + "template"
+	// CfgVOFqanTemplate represents string of template of FQAN
+	CfgVOFqanTemplate = cfgVOPrefix + "fqan-template"
+	// CfgVORoles represents array of Keystone roles reported in FQAN in order of preference
+	CfgVORoles = cfgVOPrefix + "roles"
+)
@@ -0,0 +1,20 @@
+package filter
+
+import (
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+)
+
+// statuses of deleted servers, they are listed until they are reclaimed or purged
+var deletedStatuses = map[string]bool{"DELETED": true, "SOFT_DELETED": true}
+
+// DeletedAt returns time when the server was deleted. The time of the last update is used since the server
+// does not change after it is deleted. The second value is false when the server is not deleted.
+func DeletedAt(server *servers.Server) (time.Time, bool) {
+	if server == nil || !deletedStatuses[server.Status] {
+		return time.Time{}, false
+	}
+
+	return server.Updated, true
+}
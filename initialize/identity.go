@@ -0,0 +1,129 @@
+package initialize
+
+import (
+	"strings"
+
+	"github.com/goat-project/goat-os/constants"
+
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/users"
+	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/spf13/viper"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// attributes of a user which may be used as the global user name
+const (
+	attributeFederated = "federated"
+	attributeExtra     = "extra"
+	attributeName      = "name"
+	attributeID        = "id"
+)
+
+// global user name is the unique id of the first federated identity, or the user name when
+// the user is not federated
+var defaultAttributes = []string{attributeFederated, attributeName}
+
+// federation of a user with identities in external identity providers as reported by Keystone
+// in the "federated" attribute of the user
+type federation struct {
+	ID        string `json:"id"`
+	Federated []struct {
+		IdpID     string `json:"idp_id"`
+		Protocols []struct {
+			ProtocolID string `json:"protocol_id"`
+			UniqueID   string `json:"unique_id"`
+		} `json:"protocols"`
+	} `json:"federated"`
+}
+
+// extractFederations returns federations of the users by user id.
+func extractFederations(page pagination.Page) map[string]federation {
+	federations := make(map[string]federation)
+
+	userPage, ok := page.(users.UserPage)
+	if !ok {
+		return federations
+	}
+
+	var fs []federation
+	if err := userPage.ExtractIntoSlicePtr(&fs, "users"); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error extract federated users")
+		return federations
+	}
+
+	for _, f := range fs {
+		federations[f.ID] = f
+	}
+
+	return federations
+}
+
+// globalUserName returns value of the first attribute of the user which is set. Attribute "federated" is
+// the unique id of the first federated identity, "federated:<idp>" or "federated:<idp>/<protocol>" the unique
+// id of the identity in the identity provider (and protocol), "extra:<field>" the value of the extra field
+// of the user (e.g. extra:eduPersonUniqueId), "name" and "id" the name and id of the user.
+func globalUserName(user users.User, f federation, attributes []string) string {
+	for _, attribute := range attributes {
+		kind, arg := attribute, ""
+		if i := strings.Index(attribute, ":"); i >= 0 {
+			kind, arg = attribute[:i], attribute[i+1:]
+		}
+
+		var value string
+
+		switch kind {
+		case attributeFederated:
+			value = federatedID(f, arg)
+		case attributeExtra:
+			value, _ = user.Extra[arg].(string)
+		case attributeName:
+			value = user.Name
+		case attributeID:
+			value = user.ID
+		default:
+			log.WithFields(log.Fields{"attribute": attribute}).Error("unknown user identity attribute")
+		}
+
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func federatedID(f federation, idpProtocol string) string {
+	idp, protocol := idpProtocol, ""
+	if i := strings.Index(idpProtocol, "/"); i >= 0 {
+		idp, protocol = idpProtocol[:i], idpProtocol[i+1:]
+	}
+
+	for _, federated := range f.Federated {
+		if idp != "" && federated.IdpID != idp {
+			continue
+		}
+
+		for _, p := range federated.Protocols {
+			if protocol != "" && p.ProtocolID != protocol {
+				continue
+			}
+
+			if p.UniqueID != "" {
+				return p.UniqueID
+			}
+		}
+	}
+
+	return ""
+}
+
+func identityAttributes() []string {
+	attributes := viper.GetStringSlice(constants.CfgUserIdentity)
+	if len(attributes) == 0 {
+		return defaultAttributes
+	}
+
+	return attributes
+}
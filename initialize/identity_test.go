@@ -0,0 +1,66 @@
+package initialize
+
+import (
+	"encoding/json"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/users"
+	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const usersFixture = `{"users": [
+	{"id": "federated-user", "name": "8f2a1c0e9d", "federated": [{"idp_id": "egi.eu",
+		"protocols": [{"protocol_id": "openid", "unique_id": "1234@egi.eu"}]}]},
+	{"id": "local-user", "name": "alice", "extra": {"eduPersonUniqueId": "alice@example.org"}}]}`
+
+var _ = ginkgo.Describe("User Identity tests", func() {
+	var (
+		usrs        []users.User
+		federations map[string]federation
+	)
+
+	ginkgo.BeforeEach(func() {
+		var body map[string]interface{}
+		gomega.Expect(json.Unmarshal([]byte(usersFixture), &body)).To(gomega.Succeed())
+
+		page := users.UserPage{LinkedPageBase: pagination.LinkedPageBase{
+			PageResult: pagination.PageResult{Result: gophercloud.Result{Body: body}},
+		}}
+
+		var err error
+		usrs, err = users.ExtractUsers(page)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		federations = extractFederations(page)
+	})
+
+	ginkgo.Context("when default attributes are used", func() {
+		ginkgo.It("should prefer the federated identity to the user name", func() {
+			gomega.Expect(globalUserName(usrs[0], federations[usrs[0].ID], defaultAttributes)).To(
+				gomega.Equal("1234@egi.eu"))
+			gomega.Expect(globalUserName(usrs[1], federations[usrs[1].ID], defaultAttributes)).To(
+				gomega.Equal("alice"))
+		})
+	})
+
+	ginkgo.Context("when the identity provider and extra fields are configured", func() {
+		attributes := []string{"federated:egi.eu/openid", "extra:eduPersonUniqueId", "id"}
+
+		ginkgo.It("should use the first attribute which is set", func() {
+			gomega.Expect(globalUserName(usrs[0], federations[usrs[0].ID], attributes)).To(
+				gomega.Equal("1234@egi.eu"))
+			gomega.Expect(globalUserName(usrs[1], federations[usrs[1].ID], attributes)).To(
+				gomega.Equal("alice@example.org"))
+		})
+	})
+
+	ginkgo.Context("when the identity provider does not match", func() {
+		ginkgo.It("should fall back to the next attribute", func() {
+			gomega.Expect(globalUserName(usrs[0], federations[usrs[0].ID], []string{"federated:other", "id"})).To(
+				gomega.Equal("federated-user"))
+		})
+	})
+})
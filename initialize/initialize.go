@@ -9,7 +9,8 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// UserIdentity returns map of user ID and user name.
+// UserIdentity returns map of user ID and global user name. The global user name is the value of the first
+// configured attribute of the user which is set - federated identity, extra field, name or id.
 func UserIdentity(r reader.Reader) map[string]string {
 	pages, err := r.ListAllUsers()
 	if err != nil {
@@ -33,11 +34,14 @@ func UserIdentity(r reader.Reader) map[string]string {
 		return nil
 	}
 
+	federations := extractFederations(u)
+	attributes := identityAttributes()
+
 	mUsers := make(map[string]string)
 
 	for _, user := range usrs {
 		if user.ID != "" {
-			mUsers[user.ID] = user.Name
+			mUsers[user.ID] = globalUserName(user, federations[user.ID], attributes)
 		}
 	}
 
@@ -4,7 +4,11 @@ import (
 	"time"
 
 	"github.com/goat-project/goat-os/resource"
+	clusterReader "github.com/goat-project/goat-os/resource/cluster/reader"
+	gpuReader "github.com/goat-project/goat-os/resource/gpu/reader"
+	loadBalancerReader "github.com/goat-project/goat-os/resource/loadbalancer/reader"
 	networkReader "github.com/goat-project/goat-os/resource/network/reader"
+	quotaReader "github.com/goat-project/goat-os/resource/quota/reader"
 	serverReader "github.com/goat-project/goat-os/resource/server/reader"
 	storageReader "github.com/goat-project/goat-os/resource/storage/reader"
 	"github.com/goat-project/goat-os/result"
@@ -75,6 +79,51 @@ func (r *Reader) ListAllServers(id string) (pagination.Pager, error) {
 	return r.readResources(&serverReader.Servers{ProjectID: id})
 }
 
+// GetServer gets server from Openstack.
+func (r *Reader) GetServer(id string) (result.Result, error) {
+	return r.readResource(&serverReader.Server{ID: id})
+}
+
+// ListInstanceActions lists actions of the server.
+func (r *Reader) ListInstanceActions(serverID string) (pagination.Pager, error) {
+	return r.readResources(&serverReader.InstanceActions{ServerID: serverID})
+}
+
+// ListTenantUsage lists usage of servers of the project computed by Openstack for the period.
+func (r *Reader) ListTenantUsage(projectID string, start, end time.Time) (pagination.Pager, error) {
+	return r.readResources(&serverReader.TenantUsage{ProjectID: projectID, Start: start, End: end})
+}
+
+// ListClusters lists all Magnum clusters from Openstack.
+func (r *Reader) ListClusters() (pagination.Pager, error) {
+	return r.readResources(&clusterReader.Clusters{})
+}
+
+// ListLoadBalancers lists load balancers of the project from Openstack.
+func (r *Reader) ListLoadBalancers(projectID string) (pagination.Pager, error) {
+	return r.readResources(&loadBalancerReader.LoadBalancers{ProjectID: projectID})
+}
+
+// ListAmphorae lists all amphorae of load balancers from Openstack.
+func (r *Reader) ListAmphorae() (pagination.Pager, error) {
+	return r.readResources(&loadBalancerReader.Amphorae{})
+}
+
+// GetComputeQuota gets compute quota of the project from Openstack.
+func (r *Reader) GetComputeQuota(projectID string) (result.Result, error) {
+	return r.readResource(&quotaReader.ComputeQuota{ProjectID: projectID})
+}
+
+// GetVolumeQuota gets block storage quota of the project from Openstack.
+func (r *Reader) GetVolumeQuota(projectID string) (result.Result, error) {
+	return r.readResource(&quotaReader.VolumeQuota{ProjectID: projectID})
+}
+
+// GetNetworkQuota gets network quota of the project from Openstack.
+func (r *Reader) GetNetworkQuota(projectID string) (result.Result, error) {
+	return r.readResource(&quotaReader.NetworkQuota{ProjectID: projectID})
+}
+
 // ListAllUsers lists all users from Openstack.
 func (r *Reader) ListAllUsers() (pagination.Pager, error) {
 	return r.readResources(&resource.UsersReader{})
@@ -85,11 +134,41 @@ func (r *Reader) GetUser(id string) (result.Result, error) {
 	return r.readResource(&resource.UserReader{ID: id})
 }
 
+// ListRoles lists all roles from Openstack.
+func (r *Reader) ListRoles() (pagination.Pager, error) {
+	return r.readResources(&resource.RolesReader{})
+}
+
+// ListRoleAssignments lists effective role assignments in the project.
+func (r *Reader) ListRoleAssignments(projectID string) (pagination.Pager, error) {
+	return r.readResources(&resource.RoleAssignmentsReader{ProjectID: projectID})
+}
+
+// GetDomain gets domain from Openstack.
+func (r *Reader) GetDomain(id string) (result.Result, error) {
+	return r.readResource(&resource.DomainReader{ID: id})
+}
+
 // ListAllFlavors lists all flavors from Openstack.
 func (r *Reader) ListAllFlavors() (pagination.Pager, error) {
 	return r.readResources(&resource.FlavorReader{})
 }
 
+// ListAggregates lists all host aggregates from Openstack.
+func (r *Reader) ListAggregates() (pagination.Pager, error) {
+	return r.readResources(&resource.AggregatesReader{})
+}
+
+// ListHypervisors lists all hypervisors from Openstack.
+func (r *Reader) ListHypervisors() (pagination.Pager, error) {
+	return r.readResources(&resource.HypervisorsReader{})
+}
+
+// ListBareMetalNodes lists all bare metal nodes with details from Openstack.
+func (r *Reader) ListBareMetalNodes() (pagination.Pager, error) {
+	return r.readResources(&resource.NodesReader{})
+}
+
 // ListAllImages lists all images from Openstack.
 func (r *Reader) ListAllImages(id string) (pagination.Pager, error) {
 	return r.readResources(&storageReader.Image{ProjectID: id})
@@ -100,19 +179,64 @@ func (r *Reader) ListAllShares(id string) (pagination.Pager, error) {
 	return r.readResources(&storageReader.Share{ProjectID: id})
 }
 
+// ListAllShareSnapshots lists all share snapshots.
+func (r *Reader) ListAllShareSnapshots(id string) (pagination.Pager, error) {
+	return r.readResources(&storageReader.ShareSnapshot{ProjectID: id})
+}
+
+// ListShareReplicas lists replicas of the share.
+func (r *Reader) ListShareReplicas(shareID string) (pagination.Pager, error) {
+	return r.readResources(&storageReader.ShareReplica{ShareID: shareID})
+}
+
 // ListAllVolumes lists all volumes.
 func (r *Reader) ListAllVolumes(id string) (pagination.Pager, error) {
 	return r.readResources(&storageReader.Volume{ProjectID: id})
 }
 
+// ListAllSnapshots lists all volume snapshots.
+func (r *Reader) ListAllSnapshots(id string) (pagination.Pager, error) {
+	return r.readResources(&storageReader.Snapshot{ProjectID: id})
+}
+
+// ListAllBackups lists all volume backups.
+func (r *Reader) ListAllBackups(id string) (pagination.Pager, error) {
+	return r.readResources(&storageReader.Backup{ProjectID: id})
+}
+
 // ListAllSwiftContainers lists all volumes.
 func (r *Reader) ListAllSwiftContainers() (pagination.Pager, error) {
 	return r.readResources(&storageReader.Swift{})
 }
 
-// ListFloatingIPs lists all floating ips.
-func (r *Reader) ListFloatingIPs() (pagination.Pager, error) {
-	return r.readResources(&networkReader.FloatingIP{})
+// GetContainer gets metadata of the swift container from Openstack.
+func (r *Reader) GetContainer(name string) (result.Result, error) {
+	return r.readResource(&storageReader.Container{Name: name})
+}
+
+// GetAccount gets metadata of the object storage account from Openstack.
+func (r *Reader) GetAccount() (result.Result, error) {
+	return r.readResource(&storageReader.Account{})
+}
+
+// ListFloatingIPs lists floating ips of the project.
+func (r *Reader) ListFloatingIPs(projectID string) (pagination.Pager, error) {
+	return r.readResources(&networkReader.FloatingIP{ProjectID: projectID})
+}
+
+// ListPorts lists ports of the project.
+func (r *Reader) ListPorts(projectID string) (pagination.Pager, error) {
+	return r.readResources(&networkReader.Port{ProjectID: projectID})
+}
+
+// ListRouters lists routers of the project.
+func (r *Reader) ListRouters(projectID string) (pagination.Pager, error) {
+	return r.readResources(&networkReader.Router{ProjectID: projectID})
+}
+
+// ListNetworks lists all networks.
+func (r *Reader) ListNetworks() (pagination.Pager, error) {
+	return r.readResources(&networkReader.Network{})
 }
 
 // ListAvailableProjects lists all available projects.
@@ -124,3 +248,28 @@ func (r *Reader) ListAvailableProjects() (pagination.Pager, error) {
 func (r *Reader) ListFlavorExtraSpecs(id string) (result.Result, error) {
 	return r.readResource(&resource.FlavorExtraSpecsReader{FlavorID: id})
 }
+
+// GetAllocations gets placement allocations of a consumer (server).
+func (r *Reader) GetAllocations(consumerID string) (result.Result, error) {
+	return r.readResource(&gpuReader.Allocations{ConsumerID: consumerID})
+}
+
+// GetResourceProviderTraits gets traits of a placement resource provider.
+func (r *Reader) GetResourceProviderTraits(id string) (result.Result, error) {
+	return r.readResource(&gpuReader.ResourceProviderTraits{ResourceProviderID: id})
+}
+
+// ListAcceleratorRequests lists Cyborg accelerator requests of an instance.
+func (r *Reader) ListAcceleratorRequests(instanceID string) (result.Result, error) {
+	return r.readResource(&gpuReader.AcceleratorRequests{InstanceID: instanceID})
+}
+
+// ListDeviceProfiles lists Cyborg device profiles with a given name.
+func (r *Reader) ListDeviceProfiles(name string) (result.Result, error) {
+	return r.readResource(&gpuReader.DeviceProfiles{Name: name})
+}
+
+// ListDevices lists Cyborg devices of a host.
+func (r *Reader) ListDevices(hostname string) (result.Result, error) {
+	return r.readResource(&gpuReader.Devices{Hostname: hostname})
+}
@@ -0,0 +1,16 @@
+package resource
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/aggregates"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// AggregatesReader structure for a Reader which read an array of host aggregates.
+type AggregatesReader struct {
+}
+
+// ReadResources reads an array of host aggregates.
+func (ar *AggregatesReader) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return aggregates.List(client)
+}
@@ -0,0 +1,54 @@
+package cluster
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goat-project/goat-os/filter"
+	"github.com/goat-project/goat-os/resource"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Filter contains times from/to filter records.
+type Filter struct {
+	recordsFrom time.Time
+	recordsTo   time.Time
+}
+
+// CreateFilter creates Filter.
+func CreateFilter() *Filter {
+	recordsFrom, recordsTo := filter.Period()
+
+	return &Filter{
+		recordsFrom: recordsFrom,
+		recordsTo:   recordsTo,
+	}
+}
+
+// Filtering provides filtering given resources according to configuration or command line flags
+// and writing to filtered channel.
+func (f *Filter) Filtering(res resource.Resource, filtered chan resource.Resource, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if res == nil {
+		log.WithFields(log.Fields{"err": "no cluster"}).Error("error filter empty cluster")
+		return
+	}
+
+	cluster := res.(*Resource)
+
+	from := f.recordsFrom
+	if cluster.Cluster.CreatedAt.After(from) {
+		from = cluster.Cluster.CreatedAt
+	}
+
+	if from.After(f.recordsTo) {
+		return // the cluster was created after the filtered period
+	}
+
+	cluster.From = from
+	cluster.To = f.recordsTo // deleted clusters are not listed
+
+	filtered <- cluster
+}
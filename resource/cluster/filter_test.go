@@ -0,0 +1,87 @@
+package cluster
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goat-project/goat-os/resource"
+	"github.com/gophercloud/gophercloud/openstack/containerinfra/v1/clusters"
+
+	"github.com/goat-project/goat-os/constants"
+
+	"github.com/spf13/viper"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Cluster Filter tests", func() {
+	var (
+		cluster *Resource
+		wg      sync.WaitGroup
+	)
+
+	ginkgo.JustBeforeEach(func() {
+		cluster = &Resource{Cluster: &clusters.Cluster{CreatedAt: time.Unix(1540931164, 0)}}
+	})
+
+	ginkgo.AfterEach(func() {
+		viper.Reset()
+	})
+
+	ginkgo.Describe("filter cluster", func() {
+		ginkgo.Context("when the cluster was created in the period", func() {
+			ginkgo.It("should send the cluster to the channel", func() {
+				filtered := make(chan resource.Resource, 1)
+
+				wg.Add(1)
+				CreateFilter().Filtering(cluster, filtered, &wg)
+				wg.Wait()
+
+				gomega.Expect(filtered).To(gomega.Receive(gomega.Equal(cluster)))
+				gomega.Expect(cluster.From).To(gomega.Equal(time.Unix(1540931164, 0)))
+			})
+		})
+
+		ginkgo.Context("when the cluster was created before the period", func() {
+			ginkgo.It("should send the cluster clipped to the period to the channel", func() {
+				viper.Set(constants.CfgRecordsFrom, time.Unix(1540932000, 0))
+				viper.Set(constants.CfgRecordsTo, time.Unix(1540935600, 0))
+				filtered := make(chan resource.Resource, 1)
+
+				wg.Add(1)
+				CreateFilter().Filtering(cluster, filtered, &wg)
+				wg.Wait()
+
+				gomega.Expect(filtered).To(gomega.Receive(gomega.Equal(cluster)))
+				gomega.Expect(cluster.From).To(gomega.Equal(time.Unix(1540932000, 0)))
+				gomega.Expect(cluster.To).To(gomega.Equal(time.Unix(1540935600, 0)))
+			})
+		})
+
+		ginkgo.Context("when the cluster was created after the period", func() {
+			ginkgo.It("should not send the cluster to the channel", func() {
+				viper.Set(constants.CfgRecordsTo, time.Unix(1540931000, 0))
+				filtered := make(chan resource.Resource, 1)
+
+				wg.Add(1)
+				CreateFilter().Filtering(cluster, filtered, &wg)
+				wg.Wait()
+
+				gomega.Expect(filtered).NotTo(gomega.Receive())
+			})
+		})
+
+		ginkgo.Context("when the cluster is nil", func() {
+			ginkgo.It("should not send anything to the channel", func() {
+				filtered := make(chan resource.Resource, 1)
+
+				wg.Add(1)
+				CreateFilter().Filtering(nil, filtered, &wg)
+				wg.Wait()
+
+				gomega.Expect(filtered).NotTo(gomega.Receive())
+			})
+		})
+	})
+})
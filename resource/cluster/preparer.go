@@ -0,0 +1,226 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/goat-project/goat-os/config"
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/initialize"
+	"github.com/goat-project/goat-os/reader"
+	"github.com/goat-project/goat-os/resource"
+	"github.com/goat-project/goat-os/resource/server"
+	"github.com/goat-project/goat-os/util"
+	"github.com/goat-project/goat-os/vo"
+	"github.com/goat-project/goat-os/writer"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	pb "github.com/goat-project/goat-proto-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// machine name of cluster records - name of the cluster, number of its nodes and IDs of their servers
+const machineNameFormat = "%s (%d nodes: %s)"
+
+// Preparer to prepare cluster data to specific structure for writing to Goat server.
+type Preparer struct {
+	identityReader reader.Reader
+	Writer         writer.Writer
+	userIdentity   map[string]string
+	vo             *vo.Mapper
+}
+
+// CreatePreparer creates Preparer for cluster records.
+func CreatePreparer(ir *reader.Reader, limiter *rate.Limiter, conn *grpc.ClientConn) *Preparer {
+	if ir == nil {
+		log.WithFields(log.Fields{}).Error(constants.ErrCreatePrepReaderNil)
+		return nil
+	}
+
+	if limiter == nil {
+		log.WithFields(log.Fields{}).Error(constants.ErrCreatePrepLimiterNil)
+		return nil
+	}
+
+	if conn == nil {
+		log.WithFields(log.Fields{}).Error(constants.ErrCreatePrepConnNil)
+		return nil
+	}
+
+	return &Preparer{
+		identityReader: *ir,
+		Writer:         *writer.CreateWriter(server.CreateWriter(limiter), conn),
+		vo:             vo.CreateMapper(ir),
+	}
+}
+
+// InitializeMaps reads additional data for cluster record.
+func (p *Preparer) InitializeMaps(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		p.userIdentity = initialize.UserIdentity(p.identityReader)
+		if p.userIdentity == nil {
+			log.WithFields(log.Fields{"error": "map is empty"}).Error("error create user identity map")
+		}
+	}()
+}
+
+// Preparation prepares cluster data for writing and call method to write. The cluster is written
+// as a virtual machine record with sizes of all its nodes, the record is identified by UUID
+// of the cluster. The machine name contains the number of nodes and IDs of the node servers to link
+// the record to records of the servers.
+func (p *Preparer) Preparation(acc resource.Resource, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	cluster := acc.(*Resource)
+	if cluster == nil || cluster.Cluster == nil {
+		log.WithFields(log.Fields{"error": "empty cluster"}).Error(constants.ErrPrepEmptyCluster)
+		return
+	}
+
+	u := getUsage(cluster)
+
+	if expected := cluster.Cluster.MasterCount + cluster.Cluster.NodeCount; expected != u.nodes {
+		log.WithFields(log.Fields{"id": cluster.Cluster.UUID, "expected": expected, "found": u.nodes}).Warn(
+			"cluster nodes do not match node count of the cluster")
+	}
+
+	clusterRecord := p.record(cluster, u)
+
+	if err := p.Writer.Write(clusterRecord); err != nil {
+		log.WithFields(log.Fields{"error": err, "id": cluster.Cluster.UUID}).Error(constants.ErrPrepWrite)
+	}
+}
+
+// record returns virtual machine record of the cluster with the usage of its nodes in the accounted period.
+func (p *Preparer) record(cluster *Resource, u usage) *pb.VmRecord {
+	clusterRecord := &pb.VmRecord{
+		VmUuid:              cluster.Cluster.UUID,
+		SiteName:            getSiteName(),
+		CloudComputeService: util.WrapStr(config.CloudComputeService(config.Cluster)),
+		MachineName:         fmt.Sprintf(machineNameFormat, cluster.Cluster.Name, u.nodes, u.servers),
+		LocalUserId:         util.WrapStr(cluster.Cluster.UserID),
+		LocalGroupId:        util.WrapStr(cluster.Cluster.ProjectID),
+		GlobalUserName:      getGlobalUserName(p, cluster.Cluster.UserID),
+		Fqan:                util.WrapStr(p.getFqan(cluster)),
+		Status:              util.WrapStr(cluster.Cluster.Status),
+		StartTime:           util.WrapTime(&cluster.From),
+		EndTime:             util.WrapTime(&cluster.To),
+		WallDuration:        &duration.Duration{Seconds: cluster.To.Unix() - cluster.From.Unix()},
+		CpuDuration:         &duration.Duration{Seconds: u.cpuSeconds},
+		CpuCount:            uint32(u.cpus),
+		ImageId:             util.WrapStr(cluster.Cluster.ClusterTemplateID),
+		CloudType:           getCloudType(),
+	}
+
+	if u.memory != 0 {
+		clusterRecord.Memory = &wrappers.UInt64Value{Value: uint64(u.memory)}
+	}
+
+	if u.disk != 0 {
+		clusterRecord.Disk = &wrappers.UInt64Value{Value: uint64(u.disk)}
+	}
+
+	return clusterRecord
+}
+
+// SendIdentifier sends identifier to Goat server.
+func (p *Preparer) SendIdentifier() error {
+	return p.Writer.SendIdentifier()
+}
+
+// Finish gets to know to the Goat server that a writing is finished and a response is expected.
+// Then, it closes the gRPC connection.
+func (p *Preparer) Finish() {
+	p.Writer.Finish()
+
+	log.WithFields(log.Fields{"type": "cluster"}).Debug("finished")
+}
+
+// usage of cluster nodes - number of nodes, their IDs, sums of their sizes and of durations of their CPUs
+type usage struct {
+	nodes      int
+	servers    string
+	cpus       int
+	memory     int // MB
+	disk       int // GB
+	cpuSeconds int64
+}
+
+// getUsage returns usage of the cluster nodes in the accounted period of the cluster. The nodes are
+// accounted from their creation, but not before the beginning of the period.
+func getUsage(cluster *Resource) usage {
+	u := usage{nodes: len(cluster.Nodes)}
+
+	ids := make([]string, 0, len(cluster.Nodes))
+
+	for _, node := range cluster.Nodes {
+		ids = append(ids, node.Server.ID)
+
+		if node.Flavor == nil {
+			continue
+		}
+
+		u.cpus += node.Flavor.VCPUs
+		u.memory += node.Flavor.RAM
+		u.disk += node.Flavor.Disk + node.Flavor.Ephemeral
+
+		start := node.Server.Created
+		if start.Before(cluster.From) {
+			start = cluster.From
+		}
+
+		if seconds := cluster.To.Unix() - start.Unix(); seconds > 0 {
+			u.cpuSeconds += seconds * int64(node.Flavor.VCPUs)
+		}
+	}
+
+	u.servers = strings.Join(ids, ",")
+
+	return u
+}
+
+// getFqan returns FQAN of the user of the cluster mapped to a virtual organization or the FQAN of the project
+// when the mapping is not enabled.
+func (p *Preparer) getFqan(cluster *Resource) string {
+	if p.vo.Enabled() {
+		return p.vo.Map(cluster.Project, cluster.Cluster.UserID).Fqan
+	}
+
+	return vo.LegacyFqan(cluster.Cluster.ProjectID)
+}
+
+func getSiteName() string {
+	siteName := config.SiteName(config.Cluster)
+	if siteName == "" {
+		log.WithFields(log.Fields{}).Error(constants.ErrNoSiteName) // should never happen
+	}
+
+	return siteName
+}
+
+func getCloudType() *wrappers.StringValue {
+	ct := config.CloudType(config.Cluster)
+	if ct == "" {
+		log.WithFields(log.Fields{}).Error(constants.ErrNoCloudType) // should never happen
+	}
+
+	return &wrappers.StringValue{Value: ct}
+}
+
+func getGlobalUserName(p *Preparer, userID string) *wrappers.StringValue {
+	if p.userIdentity != nil {
+		return util.WrapStr(p.userIdentity[userID])
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package cluster
+
+import (
+	"time"
+
+	"github.com/goat-project/goat-os/reader"
+	"github.com/goat-project/goat-os/vo"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/containerinfra/v1/clusters"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Cluster Preparer tests", func() {
+	ginkgo.Describe("usage", func() {
+		now := time.Unix(1600000000, 0)
+		created := now.Add(-time.Hour)
+
+		ginkgo.It("should sum sizes of the nodes and count their cpus in the accounted period", func() {
+			cluster := &Resource{
+				Cluster: &clusters.Cluster{CreatedAt: created},
+				From:    created,
+				To:      now,
+				Nodes: []Node{
+					{Server: &servers.Server{ID: "master", Created: created.Add(-time.Minute)},
+						Flavor: &flavors.Flavor{VCPUs: 2, RAM: 4096, Disk: 20}},
+					{Server: &servers.Server{ID: "node", Created: now.Add(-10 * time.Minute)},
+						Flavor: &flavors.Flavor{VCPUs: 4, RAM: 8192, Disk: 40, Ephemeral: 10}},
+					{Server: &servers.Server{ID: "unknown"}},
+				},
+			}
+
+			gomega.Expect(getUsage(cluster)).To(gomega.Equal(usage{
+				nodes:      3,
+				servers:    "master,node,unknown",
+				cpus:       6,
+				memory:     12288,
+				disk:       70,
+				cpuSeconds: 2*3600 + 4*600,
+			}))
+		})
+	})
+
+	ginkgo.Describe("record", func() {
+		ginkgo.It("should contain the number of nodes and IDs of the node servers", func() {
+			now := time.Unix(1600000000, 0)
+			cluster := &Resource{
+				Cluster: &clusters.Cluster{UUID: "cluster-id", Name: "cluster", CreatedAt: now.Add(-2 * time.Hour)},
+				From:    now.Add(-time.Hour),
+				To:      now,
+				Nodes: []Node{
+					{Server: &servers.Server{ID: "master"}, Flavor: &flavors.Flavor{VCPUs: 2, RAM: 4096}},
+					{Server: &servers.Server{ID: "node"}, Flavor: &flavors.Flavor{VCPUs: 4, RAM: 8192}},
+				},
+			}
+
+			preparer := &Preparer{vo: vo.CreateMapper(&reader.Reader{})}
+			record := preparer.record(cluster, getUsage(cluster))
+
+			gomega.Expect(record.VmUuid).To(gomega.Equal("cluster-id"))
+			gomega.Expect(record.MachineName).To(gomega.Equal("cluster (2 nodes: master,node)"))
+			gomega.Expect(record.StorageRecordId).To(gomega.BeNil())
+			gomega.Expect(record.CpuCount).To(gomega.Equal(uint32(6)))
+			gomega.Expect(record.StartTime.Seconds).To(gomega.Equal(now.Add(-time.Hour).Unix()))
+			gomega.Expect(record.EndTime.Seconds).To(gomega.Equal(now.Unix()))
+			gomega.Expect(record.WallDuration.Seconds).To(gomega.Equal(int64(3600)))
+		})
+	})
+})
@@ -0,0 +1,173 @@
+package cluster
+
+import (
+	"sync"
+
+	"github.com/goat-project/goat-os/auth"
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/reader"
+	"github.com/goat-project/goat-os/resource"
+	"github.com/goat-project/goat-os/resource/server"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/containerinfra/v1/clusters"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Processor to process cluster's data.
+type Processor struct {
+	reader reader.Reader
+
+	clustersOnce sync.Once
+	clusters     map[string][]clusters.Cluster
+}
+
+// CreateProcessor creates processor with reader.
+func CreateProcessor(r *reader.Reader) *Processor {
+	if r == nil {
+		log.WithFields(log.Fields{}).Error(constants.ErrCreateProcReaderNil)
+		return nil
+	}
+
+	return &Processor{
+		reader: *r,
+	}
+}
+
+// Reader gets reader.
+func (p *Processor) Reader() *reader.Reader {
+	return &p.reader
+}
+
+// Process provides listing of the clusters of the project and grouping of the project's servers
+// by the clusters. Servers which do not belong to any cluster are not processed.
+func (p *Processor) Process(project projects.Project, osClient *gophercloud.ProviderClient, read chan resource.Resource,
+	wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	projectClusters := p.listClusters(osClient)[project.ID]
+	if len(projectClusters) == 0 {
+		return // the project does not have any cluster
+	}
+
+	cClient, err := auth.CreateComputeV2EmbeddedFlavorServiceClient(osClient)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("unable to create Compute V2 service client")
+		return
+	}
+
+	servs, err := reader.CreateReader(cClient).ListAllServers(project.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error list servers")
+		return
+	}
+
+	pages, err := servs.AllPages() // todo add openstack pagination and wg
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error get server pages")
+		return
+	}
+
+	s, err := servers.ExtractServers(pages)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error extract servers")
+		return
+	}
+
+	for i := range projectClusters {
+		read <- &Resource{Project: &project, Cluster: &projectClusters[i], Nodes: nodes(&projectClusters[i], s)}
+	}
+}
+
+// listClusters returns clusters by their projects, the clusters are read only once.
+func (p *Processor) listClusters(osClient *gophercloud.ProviderClient) map[string][]clusters.Cluster {
+	p.clustersOnce.Do(func() {
+		p.clusters = make(map[string][]clusters.Cluster)
+
+		client, err := auth.CreateContainerInfraV1ServiceClient(osClient)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("unable to create Container Infra V1 service client")
+			return
+		}
+
+		r, err := reader.CreateReader(client).ListClusters()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error list clusters")
+			return
+		}
+
+		pages, err := r.AllPages()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error get cluster pages")
+			return
+		}
+
+		c, err := clusters.ExtractClusters(pages)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error extract clusters")
+			return
+		}
+
+		for _, cluster := range c {
+			p.clusters[cluster.ProjectID] = append(p.clusters[cluster.ProjectID], cluster)
+		}
+	})
+
+	return p.clusters
+}
+
+// nodes returns servers of the cluster nodes. A server belongs to the cluster when its metadata contains
+// UUID or stack ID of the cluster or when it has an address of a master or node of the cluster.
+func nodes(cluster *clusters.Cluster, servs []servers.Server) []Node {
+	addresses := make(map[string]bool)
+	for _, address := range append(append([]string{}, cluster.MasterAddresses...), cluster.NodeAddresses...) {
+		addresses[address] = true
+	}
+
+	var n []Node
+
+	for i := range servs {
+		if hasMetadata(&servs[i], cluster.UUID, cluster.StackID) || hasAddress(&servs[i], addresses) {
+			n = append(n, Node{Server: &servs[i], Flavor: server.EmbeddedFlavor(&servs[i])})
+		}
+	}
+
+	return n
+}
+
+func hasMetadata(server *servers.Server, values ...string) bool {
+	for _, value := range server.Metadata {
+		for _, v := range values {
+			if v != "" && value == v {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func hasAddress(server *servers.Server, addresses map[string]bool) bool {
+	for _, network := range server.Addresses {
+		nics, ok := network.([]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, nic := range nics {
+			n, ok := nic.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if address, ok := n["addr"].(string); ok && addresses[address] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,42 @@
+package cluster
+
+import (
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/containerinfra/v1/clusters"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Cluster Processor tests", func() {
+	ginkgo.Describe("nodes", func() {
+		cluster := &clusters.Cluster{UUID: "cluster-id", StackID: "stack-id", MasterAddresses: []string{"10.0.0.5"},
+			NodeAddresses: []string{"10.0.0.6"}}
+
+		servs := []servers.Server{
+			{ID: "master", Addresses: map[string]interface{}{"private": []interface{}{
+				map[string]interface{}{"addr": "10.0.0.5", "version": float64(4)}}}},
+			{ID: "node", Metadata: map[string]string{"metering.server_group": "stack-id"},
+				Flavor: map[string]interface{}{"original_name": "m1.large", "vcpus": float64(4), "ram": float64(8192),
+					"disk": float64(40), "ephemeral": float64(0), "swap": float64(0)}},
+			{ID: "other", Addresses: map[string]interface{}{"private": []interface{}{
+				map[string]interface{}{"addr": "10.0.0.7"}}}, Metadata: map[string]string{"name": "other"}},
+		}
+
+		ginkgo.It("should return servers with metadata or addresses of the cluster", func() {
+			n := nodes(cluster, servs)
+
+			gomega.Expect(n).To(gomega.HaveLen(2))
+			gomega.Expect(n[0].Server.ID).To(gomega.Equal("master"))
+			gomega.Expect(n[0].Flavor).To(gomega.BeNil())
+			gomega.Expect(n[1].Server.ID).To(gomega.Equal("node"))
+			gomega.Expect(n[1].Flavor).To(gomega.Equal(&flavors.Flavor{Name: "m1.large", VCPUs: 4, RAM: 8192,
+				Disk: 40}))
+		})
+
+		ginkgo.It("should not return any server of other clusters", func() {
+			gomega.Expect(nodes(&clusters.Cluster{UUID: "other-id"}, servs)).To(gomega.BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,16 @@
+package reader
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/containerinfra/v1/clusters"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// Clusters structure for a Reader which reads an array of Magnum clusters with details.
+type Clusters struct {
+}
+
+// ReadResources reads clusters. Administrators get clusters of all projects.
+func (c *Clusters) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return clusters.ListDetail(client, nil)
+}
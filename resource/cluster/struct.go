@@ -0,0 +1,31 @@
+package cluster
+
+import (
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/containerinfra/v1/clusters"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+)
+
+// Resource represents "Cluster Resource" with information about project, Magnum cluster, servers
+// of its nodes and the period the cluster is accounted for.
+type Resource struct {
+	Project *projects.Project
+	Cluster *clusters.Cluster
+	Nodes   []Node
+	From    time.Time
+	To      time.Time
+}
+
+// Node represents server of a cluster node with its flavor.
+type Node struct {
+	Server *servers.Server
+	Flavor *flavors.Flavor
+}
+
+// UnmarshalJSON function to implement Resource interface.
+func (c *Resource) UnmarshalJSON(b []byte) error {
+	return c.Project.UnmarshalJSON(b)
+}
@@ -0,0 +1,17 @@
+package resource
+
+import (
+	"github.com/goat-project/goat-os/result"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/domains"
+)
+
+// DomainReader structure for a Reader which read a domain by ID.
+type DomainReader struct {
+	ID string
+}
+
+// ReadResource reads a domain by ID.
+func (dr *DomainReader) ReadResource(client *gophercloud.ServiceClient) result.Result {
+	return domains.Get(client, dr.ID)
+}
@@ -0,0 +1,317 @@
+package gpu
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	gpuReader "github.com/goat-project/goat-os/resource/gpu/reader"
+	"github.com/goat-project/goat-os/util"
+
+	"github.com/gophercloud/gophercloud/openstack/placement/v1/resourceproviders"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// gpu discovery methods
+const (
+	discoveryFlavor    = "flavor"
+	discoveryPlacement = "placement"
+	discoveryCyborg    = "cyborg"
+)
+
+// default discovery settings used when nothing is set in configuration
+var (
+	defaultDiscovery       = []string{discoveryFlavor}
+	defaultResourceClasses = []string{"VGPU", "PGPU"}
+)
+
+// PCI vendor IDs reported by Cyborg
+var vendorNames = map[string]string{
+	"10de": "NVIDIA",
+	"1002": "AMD",
+	"8086": "Intel",
+}
+
+const (
+	customTraitPrefix = "CUSTOM_"
+	resourceSpecKey   = "resources:"
+	cyborgGPUType     = "GPU"
+)
+
+// discoveryCache caches data shared by servers of all projects.
+type discoveryCache struct {
+	mu       sync.Mutex
+	traits   map[string][]string
+	profiles map[string][]map[string]string
+	devices  map[string]*gpuReader.Device
+}
+
+// placementDevice returns gpu devices allocated to the server in placement. The second value is false
+// when no resources of the gpu resource classes are allocated to the server.
+func (p *Processor) placementDevice(serverID string) (Device, bool) {
+	r, err := p.placementReader.GetAllocations(serverID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "server": serverID}).Error("error get allocations")
+		return Device{}, false
+	}
+
+	allocations, err := r.(gpuReader.AllocationsResult).Extract()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "server": serverID}).Error("error extract allocations")
+		return Device{}, false
+	}
+
+	var device Device
+	var names []string
+
+	// providers and classes are sorted, so the type and model of mixed allocations do not change between runs
+	rpIDs := make([]string, 0, len(allocations))
+	for rpID := range allocations {
+		rpIDs = append(rpIDs, rpID)
+	}
+
+	sort.Strings(rpIDs)
+
+	for _, rpID := range rpIDs {
+		resources := allocations[rpID].Resources
+
+		classes := make([]string, 0, len(resources))
+		for class := range resources {
+			classes = append(classes, class)
+		}
+
+		sort.Strings(classes)
+
+		for _, class := range classes {
+			amount := resources[class]
+			if !util.Contains(p.resourceClasses, class) || amount <= 0 {
+				continue
+			}
+
+			device.Count += float32(amount)
+			device.Type = class
+			names = append(names, p.resourceProviderTraits(rpID)...)
+		}
+	}
+
+	if device.Count == 0 {
+		return Device{}, false
+	}
+
+	device.Model = customTrait(names)
+	p.rules.Describe(&device, names...)
+
+	return device, true
+}
+
+// cyborgDevice returns gpu devices bound to the server by Cyborg accelerator requests. The second value
+// is false when no gpu is bound to the server.
+func (p *Processor) cyborgDevice(serverID string) (Device, bool) {
+	r, err := p.acceleratorReader.ListAcceleratorRequests(serverID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "server": serverID}).Error("error list accelerator requests")
+		return Device{}, false
+	}
+
+	arqs, err := r.(gpuReader.AcceleratorRequestsResult).Extract()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "server": serverID}).Error("error extract accelerator requests")
+		return Device{}, false
+	}
+
+	var device Device
+	var names []string
+
+	for _, arq := range arqs {
+		if arq.DeviceRPUUID == "" {
+			continue // the request is not bound to any device
+		}
+
+		group := p.profileGroup(arq.DeviceProfileName, arq.DeviceProfileGroupID)
+		hostDevice := p.hostDevice(arq.Hostname)
+
+		if !p.requestsGPU(group) && (hostDevice == nil || hostDevice.Type != cyborgGPUType) {
+			continue // other accelerator, e.g. FPGA
+		}
+
+		device.Count++
+		names = append(names, p.resourceProviderTraits(arq.DeviceRPUUID)...)
+		names = append(names, groupTraits(group)...)
+		names = append(names, arq.DeviceProfileName)
+
+		if hostDevice != nil {
+			device.Type = hostDevice.Type
+			if vendor, ok := vendorNames[strings.ToLower(hostDevice.Vendor)]; ok {
+				device.Type = vendor
+			}
+
+			device.Model = hostDevice.Model
+			names = append(names, hostDevice.Model)
+		}
+	}
+
+	if device.Count == 0 {
+		return Device{}, false
+	}
+
+	if device.Model == "" {
+		device.Model = customTrait(names)
+	}
+
+	p.rules.Describe(&device, names...)
+
+	return device, true
+}
+
+// requestsGPU returns true when the device profile group requests any of the gpu resource classes.
+func (p *Processor) requestsGPU(group map[string]string) bool {
+	for key := range group {
+		if strings.HasPrefix(key, resourceSpecKey) &&
+			util.Contains(p.resourceClasses, strings.TrimPrefix(key, resourceSpecKey)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Processor) resourceProviderTraits(id string) []string {
+	p.cache.mu.Lock()
+	traits, ok := p.cache.traits[id]
+	p.cache.mu.Unlock()
+
+	if ok || p.placementReader == nil {
+		return traits
+	}
+
+	r, err := p.placementReader.GetResourceProviderTraits(id)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "provider": id}).Error("error get resource provider traits")
+		return nil
+	}
+
+	rpTraits, err := r.(resourceproviders.GetTraitsResult).Extract()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "provider": id}).Error("error extract resource provider traits")
+		return nil
+	}
+
+	traits = rpTraits.Traits
+	sort.Strings(traits)
+
+	p.cache.mu.Lock()
+	p.cache.traits[id] = traits
+	p.cache.mu.Unlock()
+
+	return traits
+}
+
+func (p *Processor) profileGroup(name string, groupID int) map[string]string {
+	p.cache.mu.Lock()
+	groups, ok := p.cache.profiles[name]
+	p.cache.mu.Unlock()
+
+	if !ok {
+		r, err := p.acceleratorReader.ListDeviceProfiles(name)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "profile": name}).Error("error list device profiles")
+			return nil
+		}
+
+		profiles, err := r.(gpuReader.DeviceProfilesResult).Extract()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "profile": name}).Error("error extract device profiles")
+			return nil
+		}
+
+		for _, profile := range profiles {
+			if profile.Name == name {
+				groups = profile.Groups
+				break
+			}
+		}
+
+		p.cache.mu.Lock()
+		p.cache.profiles[name] = groups
+		p.cache.mu.Unlock()
+	}
+
+	if groupID < 0 || groupID >= len(groups) {
+		return nil
+	}
+
+	return groups[groupID]
+}
+
+// hostDevice returns the gpu device of the host or nil when the host has no gpu or more gpu models.
+func (p *Processor) hostDevice(hostname string) *gpuReader.Device {
+	if hostname == "" {
+		return nil
+	}
+
+	p.cache.mu.Lock()
+	device, ok := p.cache.devices[hostname]
+	p.cache.mu.Unlock()
+
+	if ok {
+		return device
+	}
+
+	r, err := p.acceleratorReader.ListDevices(hostname)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "host": hostname}).Error("error list devices")
+		return nil
+	}
+
+	devices, err := r.(gpuReader.DevicesResult).Extract()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "host": hostname}).Error("error extract devices")
+		return nil
+	}
+
+	for i := range devices {
+		if devices[i].Type != cyborgGPUType {
+			continue
+		}
+
+		if device != nil && (device.Vendor != devices[i].Vendor || device.Model != devices[i].Model) {
+			device = nil // more gpu models, the device of the request is unknown
+			break
+		}
+
+		device = &devices[i]
+	}
+
+	p.cache.mu.Lock()
+	p.cache.devices[hostname] = device
+	p.cache.mu.Unlock()
+
+	return device
+}
+
+// groupTraits returns traits required by a device profile group.
+func groupTraits(group map[string]string) []string {
+	var traits []string
+
+	for key, value := range group {
+		if strings.HasPrefix(key, "trait:") && value == "required" {
+			traits = append(traits, strings.TrimPrefix(key, "trait:"))
+		}
+	}
+
+	sort.Strings(traits)
+
+	return traits
+}
+
+// customTrait returns the first custom trait, custom traits usually describe a gpu model or a vgpu type.
+func customTrait(traits []string) string {
+	for _, trait := range traits {
+		if strings.HasPrefix(trait, customTraitPrefix) {
+			return trait
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,142 @@
+package gpu
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/goat-project/goat-os/reader"
+	gpuReader "github.com/goat-project/goat-os/resource/gpu/reader"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const (
+	allocationsFixture = `{"allocations": {
+		"rp-host": {"generation": 3, "resources": {"VCPU": 4, "MEMORY_MB": 8192}},
+		"rp-vgpu": {"generation": 1, "resources": {"VGPU": 2}}}}`
+	mixedAllocationsFixture = `{"allocations": {
+		"rp-vgpu": {"generation": 1, "resources": {"VGPU": 2}},
+		"rp-pgpu": {"generation": 1, "resources": {"PGPU": 1}}}}`
+	noGPUAllocationsFixture = `{"allocations": {"rp-host": {"generation": 3, "resources": {"VCPU": 1}}}}`
+	vgpuTraitsFixture       = `{"resource_provider_generation": 1, "traits": ["CUSTOM_NVIDIA_222", "HW_GPU_API_VULKAN"]}`
+	pgpuTraitsFixture       = `{"resource_provider_generation": 1, "traits": ["CUSTOM_GPU_NVIDIA"]}`
+	arqsFixture             = `{"arqs": [
+		{"uuid": "arq-1", "state": "Bound", "device_profile_name": "gpu-profile", "device_profile_group_id": 0,
+		 "hostname": "compute-1", "device_rp_uuid": "rp-pgpu", "instance_uuid": "server-cyborg"},
+		{"uuid": "arq-2", "state": "Bound", "device_profile_name": "gpu-profile", "device_profile_group_id": 0,
+		 "hostname": "compute-1", "device_rp_uuid": "rp-pgpu", "instance_uuid": "server-cyborg"},
+		{"uuid": "arq-3", "state": "Initial", "device_profile_name": "gpu-profile", "device_profile_group_id": 0,
+		 "hostname": "", "device_rp_uuid": "", "instance_uuid": "server-cyborg"}]}`
+	deviceProfilesFixture = `{"device_profiles": [{"uuid": "dp-1", "name": "gpu-profile",
+		"groups": [{"resources:PGPU": "1", "trait:CUSTOM_GPU_NVIDIA": "required"}]}]}`
+	devicesFixture = `{"devices": [{"uuid": "dev-1", "type": "GPU", "vendor": "10de", "model": "A100",
+		"hostname": "compute-1"}]}`
+)
+
+var _ = ginkgo.Describe("GPU Discovery tests", func() {
+	var (
+		server *httptest.Server
+		proc   *Processor
+	)
+
+	ginkgo.BeforeEach(func() {
+		mux := http.NewServeMux()
+		fixture := func(path, body string) {
+			mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, body)
+			})
+		}
+
+		fixture("/allocations/server-vgpu", allocationsFixture)
+		fixture("/allocations/server-mixed", mixedAllocationsFixture)
+		fixture("/allocations/server-cpu", noGPUAllocationsFixture)
+		fixture("/resource_providers/rp-vgpu/traits", vgpuTraitsFixture)
+		fixture("/resource_providers/rp-pgpu/traits", pgpuTraitsFixture)
+		fixture("/accelerator_requests", arqsFixture)
+		fixture("/device_profiles", deviceProfilesFixture)
+		fixture("/devices", devicesFixture)
+
+		server = httptest.NewServer(mux)
+
+		client := &gophercloud.ServiceClient{
+			ProviderClient: &gophercloud.ProviderClient{TokenID: "token"},
+			Endpoint:       server.URL + "/",
+		}
+
+		proc = &Processor{
+			placementReader:   reader.CreateReader(client),
+			acceleratorReader: reader.CreateReader(client),
+			rules:             CreateRules(),
+			resourceClasses:   defaultResourceClasses,
+			cache: &discoveryCache{
+				traits:   make(map[string][]string),
+				profiles: make(map[string][]map[string]string),
+				devices:  make(map[string]*gpuReader.Device),
+			},
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+	})
+
+	ginkgo.Describe("discover gpu in placement", func() {
+		ginkgo.Context("when vgpu resources are allocated to the server", func() {
+			ginkgo.It("should report the number of vgpus and the vgpu type", func() {
+				device, ok := proc.placementDevice("server-vgpu")
+
+				gomega.Expect(ok).To(gomega.BeTrue())
+				gomega.Expect(device).To(gomega.Equal(Device{Count: 2, Type: "VGPU", Model: "CUSTOM_NVIDIA_222"}))
+			})
+		})
+
+		ginkgo.Context("when gpu resources of several providers are allocated to the server", func() {
+			ginkgo.It("should report the type and model in the order of the providers", func() {
+				for i := 0; i < 10; i++ {
+					device, ok := proc.placementDevice("server-mixed")
+
+					gomega.Expect(ok).To(gomega.BeTrue())
+					gomega.Expect(device).To(gomega.Equal(Device{Count: 3, Type: "VGPU", Model: "CUSTOM_GPU_NVIDIA"}))
+				}
+			})
+		})
+
+		ginkgo.Context("when no gpu resources are allocated to the server", func() {
+			ginkgo.It("should not report any gpu", func() {
+				_, ok := proc.placementDevice("server-cpu")
+
+				gomega.Expect(ok).To(gomega.BeFalse())
+			})
+		})
+	})
+
+	ginkgo.Describe("discover gpu in cyborg", func() {
+		ginkgo.Context("when accelerator requests are bound to the server", func() {
+			ginkgo.It("should report bound devices with vendor and model", func() {
+				device, ok := proc.cyborgDevice("server-cyborg")
+
+				gomega.Expect(ok).To(gomega.BeTrue())
+				gomega.Expect(device).To(gomega.Equal(Device{Count: 2, Type: "NVIDIA", Model: "A100"}))
+			})
+		})
+	})
+
+	ginkgo.Describe("discover gpu without service clients", func() {
+		ginkgo.It("should skip placement and cyborg discovery", func() {
+			proc.discovery = []string{discoveryFlavor, discoveryPlacement, discoveryCyborg}
+			proc.placementReader, proc.acceleratorReader = nil, nil
+
+			gpu := proc.discover(&servers.Server{ID: "server-vgpu"}, map[string]*flavors.Flavor{},
+				make(map[string]*Resource))
+
+			gomega.Expect(gpu).To(gomega.BeNil())
+		})
+	})
+})
@@ -2,26 +2,79 @@ package gpu
 
 import (
 	"sync"
+	"time"
 
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/filter"
 	"github.com/goat-project/goat-os/resource"
+
+	"github.com/spf13/viper"
+
+	log "github.com/sirupsen/logrus"
 )
 
-// Filter to filter network data.
+// Filter contains times from/to filter gpu records.
 type Filter struct {
+	recordsFrom time.Time
+	recordsTo   time.Time
 }
 
-// CreateFilter creates Filter.
+// CreateFilter creates Filter. When neither time from nor a period is set, the records are filtered
+// from the first day of the month of time to.
 func CreateFilter() *Filter {
-	return &Filter{}
+	recordsFrom, recordsTo := filter.Period()
+
+	if recordsFrom.Equal(time.Time{}) && viper.GetString(constants.CfgRecordsForPeriod) == "" {
+		recordsFrom = firstOfMonth(recordsTo)
+	}
+
+	return &Filter{
+		recordsFrom: recordsFrom,
+		recordsTo:   recordsTo,
+	}
 }
 
-// Filtering - only for VM relevant.
-func (f *Filter) Filtering(network resource.Resource, filtered chan resource.Resource, wg *sync.WaitGroup) {
+// Filtering provides filtering of the gpu servers active in the given period, sets the accounted period
+// of the server, which ends when the server is deleted, and writes it to filtered channel.
+func (f *Filter) Filtering(res resource.Resource, filtered chan resource.Resource, wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	if network == nil {
+	if res == nil {
 		return
 	}
 
-	filtered <- network
+	gpu, ok := res.(*Resource)
+	if !ok || gpu.Server == nil {
+		log.WithFields(log.Fields{"err": "no server"}).Error("error filter empty gpu")
+		return
+	}
+
+	from := f.recordsFrom
+	if gpu.Server.Created.After(from) {
+		from = gpu.Server.Created
+	}
+
+	if from.After(f.recordsTo) {
+		return // the server was created after the filtered period
+	}
+
+	to := f.recordsTo
+	if deleted, ok := filter.DeletedAt(gpu.Server); ok && deleted.Before(to) {
+		to = deleted
+	}
+
+	if to.Before(f.recordsFrom) {
+		return // the server was deleted before the filtered period
+	}
+
+	gpu.From = from
+	gpu.To = to
+
+	filtered <- gpu
+}
+
+func firstOfMonth(t time.Time) time.Time {
+	year, month, _ := t.Date()
+
+	return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
 }
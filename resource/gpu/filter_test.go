@@ -2,10 +2,15 @@ package gpu
 
 import (
 	"sync"
+	"time"
 
-	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 
+	"github.com/goat-project/goat-os/constants"
 	"github.com/goat-project/goat-os/resource"
+
+	"github.com/spf13/viper"
+
 	"github.com/onsi/ginkgo"
 	"github.com/onsi/gomega"
 )
@@ -13,7 +18,7 @@ import (
 var _ = ginkgo.Describe("GPU Filter tests", func() {
 	var (
 		filter   *Filter
-		res      resource.Resource
+		res      *Resource
 		filtered chan resource.Resource
 		wg       sync.WaitGroup
 	)
@@ -23,17 +28,98 @@ var _ = ginkgo.Describe("GPU Filter tests", func() {
 		wg.Add(1)
 	})
 
+	ginkgo.AfterEach(func() {
+		viper.Set(constants.CfgRecordsFrom, time.Time{})
+		viper.Set(constants.CfgRecordsTo, time.Time{})
+	})
+
+	ginkgo.Describe("create filter", func() {
+		ginkgo.Context("when no values are set", func() {
+			ginkgo.It("should create filter from the first day of the current month", func() {
+				now := time.Now()
+
+				gomega.Expect(filter.recordsFrom).To(gomega.Equal(firstOfMonth(now)))
+				gomega.Expect(filter.recordsTo).To(gomega.BeTemporally("~", now, time.Minute))
+			})
+		})
+
+		ginkgo.Context("when time from is set", func() {
+			ginkgo.BeforeEach(func() {
+				viper.Set(constants.CfgRecordsFrom, time.Date(2021, time.March, 15, 0, 0, 0, 0, time.UTC))
+			})
+
+			ginkgo.It("should create filter from the given time", func() {
+				gomega.Expect(filter.recordsFrom).To(gomega.Equal(time.Date(2021, time.March, 15, 0, 0, 0, 0, time.UTC)))
+			})
+		})
+	})
+
 	ginkgo.Describe("filter gpu", func() {
 		ginkgo.Context("when channel is empty and resource correct", func() {
 			ginkgo.BeforeEach(func() {
-				res = &images.Image{ID: "1"}
+				res = &Resource{Server: &servers.Server{ID: "1", Created: time.Now().Add(-time.Minute)}}
+				filtered = make(chan resource.Resource)
+			})
+
+			ginkgo.It("should post gpu with accounted period to the channel", func(done ginkgo.Done) {
+				go filter.Filtering(res, filtered, &wg)
+
+				gomega.Expect(<-filtered).To(gomega.Equal(res))
+				gomega.Expect(res.From).To(gomega.Equal(res.Server.Created))
+				gomega.Expect(res.To).To(gomega.Equal(filter.recordsTo))
+
+				close(done)
+			}, 0.2)
+		})
+
+		ginkgo.Context("when channel is empty and server was created after the period", func() {
+			ginkgo.BeforeEach(func() {
+				viper.Set(constants.CfgRecordsTo, time.Now().Add(-24*time.Hour))
+				res = &Resource{Server: &servers.Server{ID: "1", Created: time.Now()}}
 				filtered = make(chan resource.Resource)
 			})
 
-			ginkgo.It("should post gpu to the channel", func(done ginkgo.Done) {
+			ginkgo.It("should not post gpu to the channel", func(done ginkgo.Done) {
+				go filter.Filtering(res, filtered, &wg)
+
+				gomega.Expect(filtered).To(gomega.BeEmpty())
+
+				close(done)
+			}, 0.2)
+		})
+
+		ginkgo.Context("when channel is empty and server was deleted in the period", func() {
+			var deleted time.Time
+
+			ginkgo.BeforeEach(func() {
+				deleted = time.Now().Add(-time.Minute)
+				res = &Resource{Server: &servers.Server{ID: "1", Created: time.Now().Add(-time.Hour),
+					Status: "SOFT_DELETED", Updated: deleted}}
+				filtered = make(chan resource.Resource)
+			})
+
+			ginkgo.It("should post gpu accounted until the deletion to the channel", func(done ginkgo.Done) {
 				go filter.Filtering(res, filtered, &wg)
 
 				gomega.Expect(<-filtered).To(gomega.Equal(res))
+				gomega.Expect(res.To).To(gomega.Equal(deleted))
+
+				close(done)
+			}, 0.2)
+		})
+
+		ginkgo.Context("when channel is empty and server was deleted before the period", func() {
+			ginkgo.BeforeEach(func() {
+				viper.Set(constants.CfgRecordsFrom, time.Now().Add(-time.Hour))
+				res = &Resource{Server: &servers.Server{ID: "1", Created: time.Now().Add(-48 * time.Hour),
+					Status: "DELETED", Updated: time.Now().Add(-24 * time.Hour)}}
+				filtered = make(chan resource.Resource)
+			})
+
+			ginkgo.It("should not post gpu to the channel", func(done ginkgo.Done) {
+				go filter.Filtering(res, filtered, &wg)
+
+				gomega.Expect(filtered).To(gomega.BeEmpty())
 
 				close(done)
 			}, 0.2)
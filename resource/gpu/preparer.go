@@ -6,11 +6,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/goat-project/goat-os/benchmark"
+	"github.com/goat-project/goat-os/config"
 	"github.com/goat-project/goat-os/constants"
 	"github.com/goat-project/goat-os/initialize"
 	"github.com/goat-project/goat-os/reader"
 	"github.com/goat-project/goat-os/resource"
 	"github.com/goat-project/goat-os/util"
+	"github.com/goat-project/goat-os/vo"
 	"github.com/goat-project/goat-os/writer"
 
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
@@ -19,7 +22,6 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/golang/protobuf/ptypes/wrappers"
-	"github.com/spf13/viper"
 
 	pb "github.com/goat-project/goat-proto-go"
 	log "github.com/sirupsen/logrus"
@@ -31,6 +33,8 @@ type Preparer struct {
 	computeReader  reader.Reader
 	Writer         writer.Writer
 	userIdentity   map[string]string
+	vo             *vo.Mapper
+	benchmark      *benchmark.Mapper
 }
 
 // CreatePreparer creates Preparer for virtual machine records.
@@ -58,6 +62,8 @@ func CreatePreparer(ir *reader.Reader, cr *reader.Reader, limiter *rate.Limiter,
 	return &Preparer{
 		identityReader: *ir,
 		computeReader:  *cr,
+		vo:             vo.CreateMapper(ir),
+		benchmark:      benchmark.CreateMapper(cr),
 		Writer:         *writer.CreateWriter(CreateWriter(limiter), conn),
 	}
 }
@@ -86,55 +92,71 @@ func (p *Preparer) Preparation(acc resource.Resource, wg *sync.WaitGroup) {
 		return
 	}
 
-	timeNow := time.Now()
-	currentYear, currentMonth, _ := timeNow.Date()
-	currentLocation := timeNow.Location()
-	// unix of the first day of this month
-	firstOfMonth := time.Date(currentYear, currentMonth, 1, 0, 0, 0, 0, currentLocation).Unix()
-	// accounting time - the first day of the month or the time when the server was created, in case it was created
-	// during this month
-	accountingTime := gpu.Server.Created.Unix()
-	if firstOfMonth > accountingTime { // if the server was created this month
-		accountingTime = firstOfMonth
-	}
-	// available duration is an active time during this month
-	availableDuration := timeNow.Unix() - accountingTime
-	if availableDuration < 0 { // should never happened
-		availableDuration = 0
-	}
+	count := float64(gpu.Device.Count)
 
-	count, err := strconv.ParseFloat(gpu.ExtraSpecs["Accelerator:Number"], 32)
-	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("error convert gpu count")
-	}
+	var cores float64
+	if cpuCores, ok := gpu.ExtraSpecs["hw:cpu_cores"]; ok {
+		scores, err := strconv.ParseFloat(cpuCores, 32)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error convert gpu cores")
+		}
 
-	scores, err := strconv.ParseFloat(gpu.ExtraSpecs["hw:cpu_cores"], 32)
-	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("error convert gpu cores")
+		cores = count * scores
 	}
 
-	cores := count * scores
-
-	gpuRecord := pb.GPURecord{
-		MeasurementMonth:     uint64(currentMonth),
-		MeasurementYear:      uint64(currentYear),
-		AssociatedRecordType: "cloud",
-		AssociatedRecord:     gpu.Server.ID,
-		GlobalUserName:       getGlobalUserName(p, gpu.Server),
-		Fqan:                 gpu.Project.Name,
-		SiteName:             getSiteName(),
-		Count:                float32(count),
-		Cores:                util.WrapUint32(fmt.Sprint(cores)),
-		ActiveDuration:       util.WrapUint64(fmt.Sprint(availableDuration)),
-		AvailableDuration:    uint64(availableDuration), // todo - uptime info from diagnostics v2.48
-		//BenchmarkType: nil,
-		//Benchmark: nil,
-		Type:  gpu.ExtraSpecs["Accelerator:Type"],
-		Model: util.WrapStr(gpu.ExtraSpecs["Accelerator:Model"]),
+	from, to := gpu.From, gpu.To
+	if to.Equal(time.Time{}) { // the period is not set by the filter, account the current month
+		to = time.Now()
+		from = firstOfMonth(to)
+		if gpu.Server.Created.After(from) {
+			from = gpu.Server.Created
+		}
 	}
 
-	if err := p.Writer.Write(&gpuRecord); err != nil {
-		log.WithFields(log.Fields{"error": err, "id": gpu.Server.ID}).Error(constants.ErrPrepWrite)
+	// one record per month, active time is clipped to the month
+	for monthStart := firstOfMonth(from); monthStart.Before(to); monthStart = monthStart.AddDate(0, 1, 0) {
+		start := monthStart
+		if from.After(start) {
+			start = from
+		}
+
+		end := monthStart.AddDate(0, 1, 0)
+		if to.Before(end) {
+			end = to
+		}
+
+		// available duration is an active time during the month
+		availableDuration := end.Unix() - start.Unix()
+		if availableDuration <= 0 {
+			continue
+		}
+
+		year, month, _ := monthStart.Date()
+
+		gpuRecord := pb.GPURecord{
+			MeasurementMonth:     uint64(month),
+			MeasurementYear:      uint64(year),
+			AssociatedRecordType: "cloud",
+			AssociatedRecord:     gpu.Server.ID,
+			GlobalUserName:       getGlobalUserName(p, gpu.Server),
+			Fqan:                 p.getFqan(gpu),
+			SiteName:             getSiteName(),
+			Count:                float32(count),
+			Cores:                util.WrapUint32(fmt.Sprint(cores)),
+			ActiveDuration:       util.WrapUint64(fmt.Sprint(availableDuration)),
+			AvailableDuration:    uint64(availableDuration), // todo - uptime info from diagnostics v2.48
+			Type:                 gpu.Device.Type,
+			Model:                util.WrapStr(gpu.Device.Model),
+		}
+
+		if b, ok := p.benchmark.Map(gpu.Host, gpu.Flavor, gpu.ExtraSpecs); ok {
+			gpuRecord.BenchmarkType = util.WrapStr(b.Type)
+			gpuRecord.Benchmark = &wrappers.FloatValue{Value: float32(b.Value)}
+		}
+
+		if err := p.Writer.Write(&gpuRecord); err != nil {
+			log.WithFields(log.Fields{"error": err, "id": gpu.Server.ID}).Error(constants.ErrPrepWrite)
+		}
 	}
 }
 
@@ -152,7 +174,7 @@ func (p *Preparer) Finish() {
 }
 
 func getSiteName() string {
-	siteName := viper.GetString(constants.CfgSiteName)
+	siteName := config.SiteName(config.GPU)
 	if siteName == "" {
 		log.WithFields(log.Fields{}).Error("no site name in configuration") // should never happen
 	}
@@ -160,6 +182,16 @@ func getSiteName() string {
 	return siteName
 }
 
+// getFqan returns FQAN of the user of the server mapped to a virtual organization or name of the project
+// when the mapping is not enabled.
+func (p *Preparer) getFqan(gpu *Resource) string {
+	if p.vo.Enabled() {
+		return p.vo.Map(gpu.Project, gpu.Server.UserID).Fqan
+	}
+
+	return gpu.Project.Name
+}
+
 func getGlobalUserName(p *Preparer, server *servers.Server) *wrappers.StringValue {
 	if p.userIdentity != nil {
 		return util.WrapStr(p.userIdentity[server.UserID])
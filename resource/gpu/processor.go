@@ -1,26 +1,35 @@
 package gpu
 
 import (
-	"fmt"
-	"strings"
 	"sync"
 
 	"github.com/goat-project/goat-os/auth"
+	"github.com/goat-project/goat-os/benchmark"
 	"github.com/goat-project/goat-os/constants"
 	"github.com/goat-project/goat-os/reader"
 	"github.com/goat-project/goat-os/resource"
+	gpuReader "github.com/goat-project/goat-os/resource/gpu/reader"
+	"github.com/goat-project/goat-os/util"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
 
+	"github.com/spf13/viper"
+
 	log "github.com/sirupsen/logrus"
 )
 
 // Processor to process GPU's data.
 type Processor struct {
-	reader reader.Reader
+	reader            reader.Reader
+	placementReader   *reader.Reader
+	acceleratorReader *reader.Reader
+	rules             *Rules
+	discovery         []string
+	resourceClasses   []string
+	cache             *discoveryCache
 }
 
 // CreateProcessor creates processor with reader.
@@ -30,8 +39,26 @@ func CreateProcessor(r *reader.Reader) *Processor {
 		return nil
 	}
 
+	discovery := viper.GetStringSlice(constants.CfgGPUDiscovery)
+	if len(discovery) == 0 {
+		discovery = defaultDiscovery
+	}
+
+	resourceClasses := viper.GetStringSlice(constants.CfgGPUResourceClasses)
+	if len(resourceClasses) == 0 {
+		resourceClasses = defaultResourceClasses
+	}
+
 	return &Processor{
-		reader: *r,
+		reader:          *r,
+		rules:           CreateRules(),
+		discovery:       discovery,
+		resourceClasses: resourceClasses,
+		cache: &discoveryCache{
+			traits:   make(map[string][]string),
+			profiles: make(map[string][]map[string]string),
+			devices:  make(map[string]*gpuReader.Device),
+		},
 	}
 }
 
@@ -43,6 +70,27 @@ func (p *Processor) createReader(osClient *gophercloud.ProviderClient) {
 	}
 
 	p.reader = *reader.CreateReader(cClient)
+
+	// discovery methods without their service client are skipped
+	p.placementReader, p.acceleratorReader = nil, nil
+
+	if util.Contains(p.discovery, discoveryPlacement) || util.Contains(p.discovery, discoveryCyborg) {
+		pClient, err := auth.CreatePlacementV1ServiceClient(osClient)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("unable to create Placement V1 service client")
+		} else {
+			p.placementReader = reader.CreateReader(pClient)
+		}
+	}
+
+	if util.Contains(p.discovery, discoveryCyborg) {
+		aClient, err := auth.CreateAcceleratorV2ServiceClient(osClient)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("unable to create Accelerator V2 service client")
+		} else {
+			p.acceleratorReader = reader.CreateReader(aClient)
+		}
+	}
 }
 
 // Reader gets reader.
@@ -50,95 +98,160 @@ func (p *Processor) Reader() *reader.Reader {
 	return &p.reader
 }
 
-// Process provides listing of the flavors, filtering flavors without `nvidia` in the name, listing of servers
-// without pagination (all pages extracted in one step), listing of the extra specs for servers with flavor ID null or
-// flavor name contained `nvidia`.
+// Process provides listing of the flavors and servers without pagination (all pages extracted in one step)
+// and discovery of gpu devices of the servers. Devices are detected from extra specs of the flavors according
+// to the rules, from placement allocations or from Cyborg accelerator requests. Placement and Cyborg report
+// real devices, so they take precedence over the flavor.
 func (p *Processor) Process(project projects.Project, osClient *gophercloud.ProviderClient, read chan resource.Resource,
 	wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	p.createReader(osClient)
 
-	flvrs, err := p.reader.ListAllFlavors()
+	flavorsMap := p.listFlavors()
+
+	servs, err := p.reader.ListAllServers(project.ID)
 	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("error list flavor")
+		log.WithFields(log.Fields{"error": err}).Error("error list servers")
 		return
 	}
 
-	pages, err := flvrs.AllPages() // todo add openstack pagination and wg
+	servsPages, err := servs.AllPages() // todo add openstack pagination and wg
 	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("error get flavor pages")
+		log.WithFields(log.Fields{"error": err}).Error("error get server pages")
 		return
 	}
 
-	allFlavors, err := flavors.ExtractFlavors(pages)
+	allServers, err := servers.ExtractServers(servsPages)
 	if err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("error extract servers")
 		return
 	}
 
-	flavorsMap := make(map[string]*flavors.Flavor)
-	containsNvidia := false
-
-	if len(allFlavors) == 0 {
-		log.WithFields(log.Fields{"error": err}).Error("error empty flavor list")
-	} else {
-		for i, flavor := range allFlavors {
-			if flavor.ID != "" {
-				if strings.Contains(allFlavors[i].Name, "nvidia") {
-					containsNvidia = true
-				}
-				flavorsMap[flavor.ID] = &allFlavors[i]
-			}
+	if len(allServers) < 1 {
+		return // the project does not have any server
+	}
+
+	hosts := benchmark.Hosts(servsPages)
+
+	detected := make(map[string]*Resource)
+
+	for i := range allServers {
+		gpu := p.discover(&allServers[i], flavorsMap, detected)
+		if gpu == nil {
+			continue // the server does not have any gpu
 		}
+
+		gpu.Project = &project
+		gpu.Server = &allServers[i]
+		gpu.Host = hosts[allServers[i].ID]
+
+		if fid, ok := allServers[i].Flavor["id"].(string); ok && flavorsMap[fid] != nil {
+			gpu.Flavor = flavorsMap[fid].Name
+		}
+
+		read <- gpu
 	}
+}
 
-	if !containsNvidia {
-		return // given project does not contain flavor with name `nvidia`, it does not support GPU
+// discover returns Resource with gpu devices of the server or nil when the server does not have any gpu.
+// Detection by flavor is cached for flavors of the project in the detected map.
+func (p *Processor) discover(server *servers.Server, flavorsMap map[string]*flavors.Flavor,
+	detected map[string]*Resource) *Resource {
+	var gpu *Resource
+
+	if util.Contains(p.discovery, discoveryFlavor) {
+		fid, _ := server.Flavor["id"].(string)
+
+		flavorGPU, ok := detected[fid]
+		if !ok {
+			flavorGPU = p.detect(fid, flavorsMap[fid])
+			detected[fid] = flavorGPU
+		}
+
+		if flavorGPU != nil {
+			gpu = &Resource{ExtraSpecs: flavorGPU.ExtraSpecs, Device: flavorGPU.Device}
+		}
 	}
 
-	servs, err := p.reader.ListAllServers(project.ID)
-	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("error list servers")
-		return
+	if util.Contains(p.discovery, discoveryPlacement) && p.placementReader != nil {
+		if device, ok := p.placementDevice(server.ID); ok {
+			gpu = withDevice(gpu, device)
+		}
 	}
 
-	servsPages, err := servs.AllPages() // todo add openstack pagination and wg
+	if util.Contains(p.discovery, discoveryCyborg) && p.acceleratorReader != nil {
+		if device, ok := p.cyborgDevice(server.ID); ok {
+			gpu = withDevice(gpu, device)
+		}
+	}
+
+	return gpu
+}
+
+func withDevice(gpu *Resource, device Device) *Resource {
+	if gpu == nil {
+		return &Resource{Device: device}
+	}
+
+	gpu.Device = device
+
+	return gpu
+}
+
+func (p *Processor) listFlavors() map[string]*flavors.Flavor {
+	flavorsMap := make(map[string]*flavors.Flavor)
+
+	flvrs, err := p.reader.ListAllFlavors()
 	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("error get server pages")
-		return
+		log.WithFields(log.Fields{"error": err}).Error("error list flavor")
+		return flavorsMap
 	}
 
-	allServers, err := servers.ExtractServers(servsPages)
+	pages, err := flvrs.AllPages() // todo add openstack pagination and wg
 	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("error extract servers")
-		return
+		log.WithFields(log.Fields{"error": err}).Error("error get flavor pages")
+		return flavorsMap
 	}
 
-	if len(allServers) < 1 {
-		return // the project does not have any server
+	allFlavors, err := flavors.ExtractFlavors(pages)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error extract flavors")
+		return flavorsMap
 	}
 
-	for i := range allServers {
-		fid := allServers[i].Flavor["id"]
+	for i, flavor := range allFlavors {
+		if flavor.ID != "" {
+			flavorsMap[flavor.ID] = &allFlavors[i]
+		}
+	}
 
-		flavor := flavorsMap[fid.(string)]
+	return flavorsMap
+}
 
-		// condition takes only flavors with `nvidia` or broken flavors with old/null ID
-		if flavor == nil || strings.Contains(flavor.Name, "nvidia") {
-			eSpecs, err := p.reader.ListFlavorExtraSpecs(fmt.Sprint(fid))
-			if err != nil {
-				log.WithFields(log.Fields{"error": err}).Error("error list extra specs")
-				continue
-			}
+// detect lists extra specs of the flavor and returns Resource with detected gpu devices or nil
+// when the flavor does not provide any gpu. The flavor may be nil for deleted flavors.
+func (p *Processor) detect(fid string, flavor *flavors.Flavor) *Resource {
+	var name string
+	if flavor != nil {
+		name = flavor.Name
+	}
 
-			extraSpecs, err := eSpecs.(flavors.ListExtraSpecsResult).Extract()
-			if err != nil {
-				log.WithFields(log.Fields{"error": err}).Error("error extract servers")
-				return
-			}
+	var extraSpecs map[string]string
 
-			read <- &Resource{Project: &project, Server: &allServers[i], ExtraSpecs: extraSpecs}
+	if fid != "" {
+		eSpecs, err := p.reader.ListFlavorExtraSpecs(fid)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "flavor": fid}).Error("error list extra specs")
+		} else if extraSpecs, err = eSpecs.(flavors.ListExtraSpecsResult).Extract(); err != nil {
+			log.WithFields(log.Fields{"error": err, "flavor": fid}).Error("error extract extra specs")
 		}
 	}
+
+	device, ok := p.rules.Detect(name, extraSpecs)
+	if !ok {
+		return nil
+	}
+
+	return &Resource{ExtraSpecs: extraSpecs, Device: device}
 }
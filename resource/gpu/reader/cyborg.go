@@ -0,0 +1,130 @@
+package reader
+
+import (
+	"net/url"
+
+	"github.com/goat-project/goat-os/result"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+// AcceleratorRequests structure for a Reader which reads Cyborg accelerator requests (ARQs) of an instance.
+type AcceleratorRequests struct {
+	InstanceID string
+}
+
+// DeviceProfiles structure for a Reader which reads Cyborg device profiles by name.
+type DeviceProfiles struct {
+	Name string
+}
+
+// Devices structure for a Reader which reads Cyborg devices of a host.
+type Devices struct {
+	Hostname string
+}
+
+// AcceleratorRequest represents a Cyborg accelerator request.
+type AcceleratorRequest struct {
+	UUID                 string `json:"uuid"`
+	State                string `json:"state"`
+	DeviceProfileName    string `json:"device_profile_name"`
+	DeviceProfileGroupID int    `json:"device_profile_group_id"`
+	Hostname             string `json:"hostname"`
+	DeviceRPUUID         string `json:"device_rp_uuid"`
+	InstanceUUID         string `json:"instance_uuid"`
+}
+
+// DeviceProfile represents a Cyborg device profile.
+type DeviceProfile struct {
+	UUID   string              `json:"uuid"`
+	Name   string              `json:"name"`
+	Groups []map[string]string `json:"groups"`
+}
+
+// Device represents a Cyborg device.
+type Device struct {
+	UUID     string `json:"uuid"`
+	Type     string `json:"type"`
+	Vendor   string `json:"vendor"`
+	Model    string `json:"model"`
+	Hostname string `json:"hostname"`
+}
+
+// AcceleratorRequestsResult represents the result of a list accelerator requests operation.
+type AcceleratorRequestsResult struct {
+	gophercloud.Result
+}
+
+// Extract returns accelerator requests.
+func (r AcceleratorRequestsResult) Extract() ([]AcceleratorRequest, error) {
+	var s struct {
+		ARQs []AcceleratorRequest `json:"arqs"`
+	}
+
+	err := r.ExtractInto(&s)
+
+	return s.ARQs, err
+}
+
+// DeviceProfilesResult represents the result of a list device profiles operation.
+type DeviceProfilesResult struct {
+	gophercloud.Result
+}
+
+// Extract returns device profiles.
+func (r DeviceProfilesResult) Extract() ([]DeviceProfile, error) {
+	var s struct {
+		DeviceProfiles []DeviceProfile `json:"device_profiles"`
+	}
+
+	err := r.ExtractInto(&s)
+
+	return s.DeviceProfiles, err
+}
+
+// DevicesResult represents the result of a list devices operation.
+type DevicesResult struct {
+	gophercloud.Result
+}
+
+// Extract returns devices.
+func (r DevicesResult) Extract() ([]Device, error) {
+	var s struct {
+		Devices []Device `json:"devices"`
+	}
+
+	err := r.ExtractInto(&s)
+
+	return s.Devices, err
+}
+
+// ReadResource reads accelerator requests of an instance.
+func (a *AcceleratorRequests) ReadResource(client *gophercloud.ServiceClient) result.Result {
+	var r AcceleratorRequestsResult
+
+	resp, err := client.Get(client.ServiceURL("accelerator_requests")+"?instance="+url.QueryEscape(a.InstanceID),
+		&r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+
+	return r
+}
+
+// ReadResource reads device profiles by name.
+func (d *DeviceProfiles) ReadResource(client *gophercloud.ServiceClient) result.Result {
+	var r DeviceProfilesResult
+
+	resp, err := client.Get(client.ServiceURL("device_profiles")+"?name="+url.QueryEscape(d.Name), &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+
+	return r
+}
+
+// ReadResource reads devices of a host.
+func (d *Devices) ReadResource(client *gophercloud.ServiceClient) result.Result {
+	var r DevicesResult
+
+	resp, err := client.Get(client.ServiceURL("devices")+"?hostname="+url.QueryEscape(d.Hostname), &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+
+	return r
+}
@@ -0,0 +1,54 @@
+package reader
+
+import (
+	"github.com/goat-project/goat-os/result"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/placement/v1/resourceproviders"
+)
+
+// Allocations structure for a Reader which reads placement allocations of a consumer (server).
+type Allocations struct {
+	ConsumerID string
+}
+
+// ResourceProviderTraits structure for a Reader which reads traits of a resource provider.
+type ResourceProviderTraits struct {
+	ResourceProviderID string
+}
+
+// Allocation represents resources allocated to a consumer from one resource provider.
+type Allocation struct {
+	Resources map[string]int `json:"resources"`
+}
+
+// AllocationsResult represents the result of a get allocations operation.
+type AllocationsResult struct {
+	gophercloud.Result
+}
+
+// Extract returns allocations of a consumer by resource provider ID.
+func (r AllocationsResult) Extract() (map[string]Allocation, error) {
+	var s struct {
+		Allocations map[string]Allocation `json:"allocations"`
+	}
+
+	err := r.ExtractInto(&s)
+
+	return s.Allocations, err
+}
+
+// ReadResource reads allocations of a consumer.
+func (a *Allocations) ReadResource(client *gophercloud.ServiceClient) result.Result {
+	var r AllocationsResult
+
+	resp, err := client.Get(client.ServiceURL("allocations", a.ConsumerID), &r.Body, nil)
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+
+	return r
+}
+
+// ReadResource reads traits of a resource provider.
+func (t *ResourceProviderTraits) ReadResource(client *gophercloud.ServiceClient) result.Result {
+	return resourceproviders.GetTraits(client, t.ResourceProviderID)
+}
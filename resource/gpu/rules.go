@@ -0,0 +1,257 @@
+package gpu
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goat-project/goat-os/constants"
+
+	"github.com/spf13/viper"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// extra specs with special meaning
+const (
+	acceleratorNumber = "Accelerator:Number"
+	acceleratorType   = "Accelerator:Type"
+	acceleratorModel  = "Accelerator:Model"
+	pciAlias          = "pci_passthrough:alias"
+	resourcesPrefix   = "resources"
+)
+
+// default rules used when no rules are set in configuration
+var (
+	defaultExtraSpecs  = []string{"resources*:VGPU", pciAlias, "Accelerator:*"}
+	defaultFlavorNames = []string{"nvidia"}
+	// pci passthrough aliases of network cards and other devices are not gpus
+	defaultPCIAliases = []string{`(?i)gpu|nvidia|tesla|quadro|radeon|instinct|amd`, `(?i)^(a|h|k|l|p|t|v|mi)\d+`}
+)
+
+// Device represents gpu devices attached to a server.
+type Device struct {
+	Count float32
+	Type  string
+	Model string
+}
+
+// Model maps devices with a name matching the pattern to the type (vendor) and model.
+type Model struct {
+	Pattern string `mapstructure:"pattern"`
+	Type    string `mapstructure:"type"`
+	Model   string `mapstructure:"model"`
+
+	re *regexp.Regexp
+}
+
+// Rules decide whether a flavor provides gpu devices and describe the devices.
+type Rules struct {
+	extraSpecs  []string
+	flavorNames []*regexp.Regexp
+	pciAliases  []*regexp.Regexp
+	models      []Model
+}
+
+// CreateRules creates Rules from configuration.
+func CreateRules() *Rules {
+	rules := &Rules{
+		extraSpecs: viper.GetStringSlice(constants.CfgGPUExtraSpecs),
+	}
+
+	if len(rules.extraSpecs) == 0 {
+		rules.extraSpecs = defaultExtraSpecs
+	}
+
+	flavorNames := viper.GetStringSlice(constants.CfgGPUFlavorNames)
+	if len(flavorNames) == 0 {
+		flavorNames = defaultFlavorNames
+	}
+
+	rules.flavorNames = compile(flavorNames, "error compile gpu flavor name pattern")
+
+	pciAliases := viper.GetStringSlice(constants.CfgGPUPCIAliases)
+	if len(pciAliases) == 0 {
+		pciAliases = defaultPCIAliases
+	}
+
+	rules.pciAliases = compile(pciAliases, "error compile gpu pci alias pattern")
+
+	var models []Model
+	if err := viper.UnmarshalKey(constants.CfgGPUModels, &models); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error read gpu models")
+	}
+
+	for i := range models {
+		re, err := regexp.Compile(models[i].Pattern)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "pattern": models[i].Pattern}).Error("error compile gpu model pattern")
+			continue
+		}
+
+		models[i].re = re
+		rules.models = append(rules.models, models[i])
+	}
+
+	return rules
+}
+
+// Detect returns gpu devices provided by a flavor with the given name and extra specs.
+// The second value is false when the flavor does not provide any gpu.
+func (r *Rules) Detect(flavorName string, extraSpecs map[string]string) (Device, bool) {
+	detected := r.matchFlavorName(flavorName)
+
+	var number, count float64
+	var aliases []string
+
+	for key, value := range extraSpecs {
+		if !r.matchExtraSpec(key) {
+			continue
+		}
+
+		if key == pciAlias {
+			names, n := r.parsePCIAlias(value)
+			if len(names) == 0 {
+				continue // none of the aliased devices is a gpu
+			}
+
+			aliases = append(aliases, names...)
+			count += n
+			detected = true
+
+			continue
+		}
+
+		detected = true
+
+		switch {
+		case key == acceleratorNumber:
+			n, err := strconv.ParseFloat(value, 32)
+			if err != nil {
+				log.WithFields(log.Fields{"error": err, "flavor": flavorName}).Error("error convert gpu count")
+				continue
+			}
+			number = n
+		case strings.HasPrefix(key, resourcesPrefix):
+			n, err := strconv.ParseFloat(value, 32)
+			if err != nil {
+				log.WithFields(log.Fields{"error": err, "flavor": flavorName}).Error("error convert gpu resources")
+				continue
+			}
+			count += n
+		}
+	}
+
+	if !detected {
+		return Device{}, false
+	}
+
+	if number > 0 {
+		count = number
+	}
+
+	if count == 0 {
+		count = 1 // the flavor is recognized only by its name or by a spec without a count
+	}
+
+	device := Device{
+		Count: float32(count),
+		Type:  extraSpecs[acceleratorType],
+		Model: extraSpecs[acceleratorModel],
+	}
+
+	r.Describe(&device, append([]string{extraSpecs[acceleratorModel], extraSpecs[acceleratorType]},
+		append(aliases, flavorName)...)...)
+
+	return device, true
+}
+
+// Describe sets type and model of the device according to the first model matching any of the names.
+func (r *Rules) Describe(device *Device, names ...string) {
+	for _, model := range r.models {
+		for _, name := range names {
+			if name == "" || !model.re.MatchString(name) {
+				continue
+			}
+
+			if model.Type != "" {
+				device.Type = model.Type
+			}
+
+			if model.Model != "" {
+				device.Model = model.Model
+			}
+
+			return
+		}
+	}
+}
+
+func (r *Rules) matchFlavorName(name string) bool {
+	return matchAny(r.flavorNames, name)
+}
+
+func matchAny(patterns []*regexp.Regexp, name string) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compile compiles the regular expressions, the invalid ones are logged and skipped.
+func compile(patterns []string, msg string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "pattern": pattern}).Error(msg)
+			continue
+		}
+
+		compiled = append(compiled, re)
+	}
+
+	return compiled
+}
+
+func (r *Rules) matchExtraSpec(key string) bool {
+	for _, pattern := range r.extraSpecs {
+		if matched, err := path.Match(pattern, key); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parsePCIAlias parses value in format "alias1:count1,alias2:count2" and returns names of aliases
+// matching the gpu alias patterns and the sum of their counts.
+func (r *Rules) parsePCIAlias(value string) ([]string, float64) {
+	var names []string
+	var count float64
+
+	for _, alias := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(alias), ":", 2)
+		if parts[0] == "" || !matchAny(r.pciAliases, parts[0]) {
+			continue
+		}
+
+		names = append(names, parts[0])
+
+		n := 1.0
+		if len(parts) == 2 {
+			if c, err := strconv.ParseFloat(parts[1], 32); err == nil {
+				n = c
+			}
+		}
+
+		count += n
+	}
+
+	return names, count
+}
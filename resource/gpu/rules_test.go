@@ -0,0 +1,135 @@
+package gpu
+
+import (
+	"github.com/goat-project/goat-os/constants"
+
+	"github.com/spf13/viper"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("GPU Rules tests", func() {
+	var rules *Rules
+
+	ginkgo.JustBeforeEach(func() {
+		rules = CreateRules()
+	})
+
+	ginkgo.AfterEach(func() {
+		viper.Set(constants.CfgGPUExtraSpecs, nil)
+		viper.Set(constants.CfgGPUFlavorNames, nil)
+		viper.Set(constants.CfgGPUPCIAliases, nil)
+		viper.Set(constants.CfgGPUModels, nil)
+	})
+
+	ginkgo.Describe("detect gpu with default rules", func() {
+		ginkgo.Context("when flavor has no gpu extra spec and name", func() {
+			ginkgo.It("should not detect gpu", func() {
+				_, ok := rules.Detect("m1.large", map[string]string{"hw:cpu_cores": "4"})
+
+				gomega.Expect(ok).To(gomega.BeFalse())
+			})
+		})
+
+		ginkgo.Context("when flavor name contains nvidia", func() {
+			ginkgo.It("should detect one gpu", func() {
+				device, ok := rules.Detect("g1.nvidia", nil)
+
+				gomega.Expect(ok).To(gomega.BeTrue())
+				gomega.Expect(device.Count).To(gomega.Equal(float32(1)))
+			})
+		})
+
+		ginkgo.Context("when flavor requests vgpu resources", func() {
+			ginkgo.It("should detect the number of vgpus", func() {
+				device, ok := rules.Detect("v1.small", map[string]string{"resources:VGPU": "2"})
+
+				gomega.Expect(ok).To(gomega.BeTrue())
+				gomega.Expect(device.Count).To(gomega.Equal(float32(2)))
+			})
+		})
+
+		ginkgo.Context("when flavor has pci passthrough aliases", func() {
+			ginkgo.It("should detect the sum of aliased devices", func() {
+				device, ok := rules.Detect("p1.large", map[string]string{pciAlias: "mi100:2, mi210"})
+
+				gomega.Expect(ok).To(gomega.BeTrue())
+				gomega.Expect(device.Count).To(gomega.Equal(float32(3)))
+			})
+
+			ginkgo.It("should count only gpu aliases", func() {
+				device, ok := rules.Detect("p1.large", map[string]string{pciAlias: "a100:2,mlx5-vf:4"})
+
+				gomega.Expect(ok).To(gomega.BeTrue())
+				gomega.Expect(device.Count).To(gomega.Equal(float32(2)))
+			})
+
+			ginkgo.It("should not detect gpu when no alias is a gpu", func() {
+				_, ok := rules.Detect("n1.large", map[string]string{pciAlias: "mlx5-vf:1, nvme:2"})
+
+				gomega.Expect(ok).To(gomega.BeFalse())
+			})
+		})
+
+		ginkgo.Context("when flavor has accelerator extra specs", func() {
+			ginkgo.It("should use accelerator number, type and model", func() {
+				device, ok := rules.Detect("a1.large", map[string]string{acceleratorNumber: "4",
+					acceleratorType: "GPU", acceleratorModel: "T4"})
+
+				gomega.Expect(ok).To(gomega.BeTrue())
+				gomega.Expect(device).To(gomega.Equal(Device{Count: 4, Type: "GPU", Model: "T4"}))
+			})
+		})
+	})
+
+	ginkgo.Describe("detect gpu with configured rules", func() {
+		ginkgo.BeforeEach(func() {
+			viper.Set(constants.CfgGPUExtraSpecs, []string{pciAlias})
+			viper.Set(constants.CfgGPUFlavorNames, []string{"^amd\\."})
+			viper.Set(constants.CfgGPUModels, []map[string]interface{}{
+				{"pattern": "(?i)mi100", "type": "AMD", "model": "Instinct MI100"},
+				{"pattern": "^amd\\.", "type": "AMD"},
+			})
+		})
+
+		ginkgo.Context("when pci alias matches a model", func() {
+			ginkgo.It("should map the alias to the type and model", func() {
+				device, ok := rules.Detect("p1.large", map[string]string{pciAlias: "MI100:1"})
+
+				gomega.Expect(ok).To(gomega.BeTrue())
+				gomega.Expect(device).To(gomega.Equal(Device{Count: 1, Type: "AMD", Model: "Instinct MI100"}))
+			})
+		})
+
+		ginkgo.Context("when flavor name matches a model", func() {
+			ginkgo.It("should map the flavor name to the type", func() {
+				device, ok := rules.Detect("amd.large", nil)
+
+				gomega.Expect(ok).To(gomega.BeTrue())
+				gomega.Expect(device.Type).To(gomega.Equal("AMD"))
+			})
+		})
+
+		ginkgo.Context("when pci aliases are configured", func() {
+			ginkgo.BeforeEach(func() {
+				viper.Set(constants.CfgGPUPCIAliases, []string{"^gpu-"})
+			})
+
+			ginkgo.It("should count only the configured aliases", func() {
+				device, ok := rules.Detect("p1.large", map[string]string{pciAlias: "gpu-a:2,MI100:1"})
+
+				gomega.Expect(ok).To(gomega.BeTrue())
+				gomega.Expect(device.Count).To(gomega.Equal(float32(2)))
+			})
+		})
+
+		ginkgo.Context("when flavor name matches only the default rule", func() {
+			ginkgo.It("should not detect gpu", func() {
+				_, ok := rules.Detect("g1.nvidia", map[string]string{"resources:VGPU": "1"})
+
+				gomega.Expect(ok).To(gomega.BeFalse())
+			})
+		})
+	})
+})
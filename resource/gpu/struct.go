@@ -1,15 +1,24 @@
 package gpu
 
 import (
+	"time"
+
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
 )
 
-// Resource represents "GPU Resource" with information about project, server and his extra specs.
+// Resource represents "GPU Resource" with information about project, server, his extra specs,
+// detected gpu devices, the host and flavor used to map the server to a benchmark and the period
+// the server is accounted for.
 type Resource struct {
 	Project    *projects.Project
 	Server     *servers.Server
 	ExtraSpecs map[string]string
+	Host       string
+	Flavor     string
+	Device     Device
+	From       time.Time
+	To         time.Time
 }
 
 // UnmarshalJSON function to implement Resource interface.
@@ -0,0 +1,16 @@
+package resource
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/hypervisors"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// HypervisorsReader structure for a Reader which read an array of hypervisors.
+type HypervisorsReader struct {
+}
+
+// ReadResources reads an array of hypervisors.
+func (hr *HypervisorsReader) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return hypervisors.List(client)
+}
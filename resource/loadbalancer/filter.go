@@ -0,0 +1,54 @@
+package loadbalancer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goat-project/goat-os/filter"
+	"github.com/goat-project/goat-os/resource"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Filter contains times from/to filter records.
+type Filter struct {
+	recordsFrom time.Time
+	recordsTo   time.Time
+}
+
+// CreateFilter creates Filter.
+func CreateFilter() *Filter {
+	recordsFrom, recordsTo := filter.Period()
+
+	return &Filter{
+		recordsFrom: recordsFrom,
+		recordsTo:   recordsTo,
+	}
+}
+
+// Filtering provides filtering given resources according to configuration or command line flags
+// and writing to filtered channel.
+func (f *Filter) Filtering(res resource.Resource, filtered chan resource.Resource, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if res == nil {
+		log.WithFields(log.Fields{"err": "no load balancer"}).Error("error filter empty load balancer")
+		return
+	}
+
+	lb := res.(*Resource)
+
+	from := f.recordsFrom
+	if lb.LoadBalancer.CreatedAt.After(from) {
+		from = lb.LoadBalancer.CreatedAt
+	}
+
+	if from.After(f.recordsTo) {
+		return // the load balancer was created after the filtered period
+	}
+
+	lb.From = from
+	lb.To = f.recordsTo // deleted load balancers are not listed
+
+	filtered <- lb
+}
@@ -0,0 +1,87 @@
+package loadbalancer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goat-project/goat-os/resource"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+
+	"github.com/goat-project/goat-os/constants"
+
+	"github.com/spf13/viper"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Load Balancer Filter tests", func() {
+	var (
+		lb *Resource
+		wg sync.WaitGroup
+	)
+
+	ginkgo.JustBeforeEach(func() {
+		lb = &Resource{LoadBalancer: &loadbalancers.LoadBalancer{CreatedAt: time.Unix(1540931164, 0)}}
+	})
+
+	ginkgo.AfterEach(func() {
+		viper.Reset()
+	})
+
+	ginkgo.Describe("filter load balancer", func() {
+		ginkgo.Context("when the load balancer was created in the period", func() {
+			ginkgo.It("should send the load balancer to the channel", func() {
+				filtered := make(chan resource.Resource, 1)
+
+				wg.Add(1)
+				CreateFilter().Filtering(lb, filtered, &wg)
+				wg.Wait()
+
+				gomega.Expect(filtered).To(gomega.Receive(gomega.Equal(lb)))
+				gomega.Expect(lb.From).To(gomega.Equal(time.Unix(1540931164, 0)))
+			})
+		})
+
+		ginkgo.Context("when the load balancer was created before the period", func() {
+			ginkgo.It("should send the load balancer clipped to the period to the channel", func() {
+				viper.Set(constants.CfgRecordsFrom, time.Unix(1540932000, 0))
+				viper.Set(constants.CfgRecordsTo, time.Unix(1540935600, 0))
+				filtered := make(chan resource.Resource, 1)
+
+				wg.Add(1)
+				CreateFilter().Filtering(lb, filtered, &wg)
+				wg.Wait()
+
+				gomega.Expect(filtered).To(gomega.Receive(gomega.Equal(lb)))
+				gomega.Expect(lb.From).To(gomega.Equal(time.Unix(1540932000, 0)))
+				gomega.Expect(lb.To).To(gomega.Equal(time.Unix(1540935600, 0)))
+			})
+		})
+
+		ginkgo.Context("when the load balancer was created after the period", func() {
+			ginkgo.It("should not send the load balancer to the channel", func() {
+				viper.Set(constants.CfgRecordsTo, time.Unix(1540931000, 0))
+				filtered := make(chan resource.Resource, 1)
+
+				wg.Add(1)
+				CreateFilter().Filtering(lb, filtered, &wg)
+				wg.Wait()
+
+				gomega.Expect(filtered).NotTo(gomega.Receive())
+			})
+		})
+
+		ginkgo.Context("when the load balancer is nil", func() {
+			ginkgo.It("should not send anything to the channel", func() {
+				filtered := make(chan resource.Resource, 1)
+
+				wg.Add(1)
+				CreateFilter().Filtering(nil, filtered, &wg)
+				wg.Wait()
+
+				gomega.Expect(filtered).NotTo(gomega.Receive())
+			})
+		})
+	})
+})
@@ -0,0 +1,167 @@
+package loadbalancer
+
+import (
+	"sync"
+
+	"github.com/goat-project/goat-os/config"
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/reader"
+	"github.com/goat-project/goat-os/resource"
+	"github.com/goat-project/goat-os/resource/server"
+	"github.com/goat-project/goat-os/util"
+	"github.com/goat-project/goat-os/vo"
+	"github.com/goat-project/goat-os/writer"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	pb "github.com/goat-project/goat-proto-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// Preparer to prepare load balancer data to specific structure for writing to Goat server.
+type Preparer struct {
+	Writer writer.Writer
+	vo     *vo.Mapper
+}
+
+// CreatePreparer creates Preparer for load balancer records.
+func CreatePreparer(ir *reader.Reader, limiter *rate.Limiter, conn *grpc.ClientConn) *Preparer {
+	if ir == nil {
+		log.WithFields(log.Fields{}).Error(constants.ErrCreatePrepReaderNil)
+		return nil
+	}
+
+	if limiter == nil {
+		log.WithFields(log.Fields{}).Error(constants.ErrCreatePrepLimiterNil)
+		return nil
+	}
+
+	if conn == nil {
+		log.WithFields(log.Fields{}).Error(constants.ErrCreatePrepConnNil)
+		return nil
+	}
+
+	return &Preparer{
+		Writer: *writer.CreateWriter(server.CreateWriter(limiter), conn),
+		vo:     vo.CreateMapper(ir),
+	}
+}
+
+// InitializeMaps does not read any additional data, load balancers do not have users.
+func (p *Preparer) InitializeMaps(wg *sync.WaitGroup) {
+	defer wg.Done()
+}
+
+// Preparation prepares load balancer data for writing and call method to write. The load balancer is written
+// as a virtual machine record with sizes of its amphora servers, the record is identified by ID of the load
+// balancer.
+func (p *Preparer) Preparation(acc resource.Resource, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	lb := acc.(*Resource)
+	if lb == nil || lb.LoadBalancer == nil {
+		log.WithFields(log.Fields{"error": "empty load balancer"}).Error(constants.ErrPrepEmptyLoadBalancer)
+		return
+	}
+
+	if err := p.Writer.Write(p.record(lb)); err != nil {
+		log.WithFields(log.Fields{"error": err, "id": lb.LoadBalancer.ID}).Error(constants.ErrPrepWrite)
+	}
+}
+
+// record returns virtual machine record of the load balancer with the size of its amphora servers
+// in the accounted period.
+func (p *Preparer) record(lb *Resource) *pb.VmRecord {
+	wallDuration := lb.To.Unix() - lb.From.Unix()
+	if wallDuration < 0 {
+		wallDuration = 0
+	}
+
+	cpus, memory, disk := getSize(lb)
+
+	lbRecord := &pb.VmRecord{
+		VmUuid:              lb.LoadBalancer.ID,
+		SiteName:            getSiteName(),
+		CloudComputeService: util.WrapStr(config.CloudComputeService(config.LoadBalancer)),
+		MachineName:         lb.LoadBalancer.Name,
+		LocalGroupId:        util.WrapStr(lb.LoadBalancer.ProjectID),
+		Fqan:                util.WrapStr(p.getFqan(lb)),
+		Status:              util.WrapStr(lb.LoadBalancer.ProvisioningStatus),
+		StartTime:           util.WrapTime(&lb.From),
+		EndTime:             util.WrapTime(&lb.To),
+		WallDuration:        &duration.Duration{Seconds: wallDuration},
+		CpuDuration:         &duration.Duration{Seconds: wallDuration * int64(cpus)},
+		CpuCount:            uint32(cpus),
+		CloudType:           getCloudType(),
+	}
+
+	if lb.PublicVIP {
+		lbRecord.PublicIpCount = &wrappers.UInt64Value{Value: 1}
+	}
+
+	if memory != 0 {
+		lbRecord.Memory = &wrappers.UInt64Value{Value: uint64(memory)}
+	}
+
+	if disk != 0 {
+		lbRecord.Disk = &wrappers.UInt64Value{Value: uint64(disk)}
+	}
+
+	return lbRecord
+}
+
+// SendIdentifier sends identifier to Goat server.
+func (p *Preparer) SendIdentifier() error {
+	return p.Writer.SendIdentifier()
+}
+
+// Finish gets to know to the Goat server that a writing is finished and a response is expected.
+// Then, it closes the gRPC connection.
+func (p *Preparer) Finish() {
+	p.Writer.Finish()
+
+	log.WithFields(log.Fields{"type": "loadbalancer"}).Debug("finished")
+}
+
+// getFqan returns FQAN of the project of the load balancer mapped to a virtual organization or the FQAN
+// of the project when the mapping is not enabled.
+func (p *Preparer) getFqan(lb *Resource) string {
+	if p.vo.Enabled() {
+		return p.vo.Map(lb.Project, "").Fqan
+	}
+
+	return vo.LegacyFqan(lb.LoadBalancer.ProjectID)
+}
+
+// getSize returns sums of CPUs, memory (MB) and disk (GB) of amphora servers of the load balancer.
+func getSize(lb *Resource) (cpus, memory, disk int) {
+	for _, flavor := range lb.Flavors {
+		cpus += flavor.VCPUs
+		memory += flavor.RAM
+		disk += flavor.Disk + flavor.Ephemeral
+	}
+
+	return cpus, memory, disk
+}
+
+func getSiteName() string {
+	siteName := config.SiteName(config.LoadBalancer)
+	if siteName == "" {
+		log.WithFields(log.Fields{}).Error(constants.ErrNoSiteName) // should never happen
+	}
+
+	return siteName
+}
+
+func getCloudType() *wrappers.StringValue {
+	ct := config.CloudType(config.LoadBalancer)
+	if ct == "" {
+		log.WithFields(log.Fields{}).Error(constants.ErrNoCloudType) // should never happen
+	}
+
+	return &wrappers.StringValue{Value: ct}
+}
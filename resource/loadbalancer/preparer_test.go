@@ -0,0 +1,71 @@
+package loadbalancer
+
+import (
+	"time"
+
+	"github.com/goat-project/goat-os/reader"
+	"github.com/goat-project/goat-os/vo"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Load Balancer Preparer tests", func() {
+	ginkgo.Describe("size", func() {
+		ginkgo.It("should sum sizes of the amphora servers", func() {
+			cpus, memory, disk := getSize(&Resource{Flavors: []*flavors.Flavor{
+				{VCPUs: 1, RAM: 1024, Disk: 2},
+				{VCPUs: 1, RAM: 1024, Disk: 2, Ephemeral: 1},
+			}})
+
+			gomega.Expect([]int{cpus, memory, disk}).To(gomega.Equal([]int{2, 2048, 5}))
+		})
+
+		ginkgo.It("should return zero sizes without amphorae", func() {
+			cpus, memory, disk := getSize(&Resource{})
+
+			gomega.Expect([]int{cpus, memory, disk}).To(gomega.Equal([]int{0, 0, 0}))
+		})
+	})
+
+	ginkgo.Describe("record", func() {
+		now := time.Unix(1600000000, 0)
+		preparer := &Preparer{vo: vo.CreateMapper(&reader.Reader{})}
+
+		ginkgo.It("should contain the size of the amphorae in the accounted period", func() {
+			record := preparer.record(&Resource{
+				LoadBalancer: &loadbalancers.LoadBalancer{ID: "lb-id", Name: "lb", ProjectID: "project",
+					ProvisioningStatus: "ACTIVE", CreatedAt: now.Add(-2 * time.Hour)},
+				Flavors:   []*flavors.Flavor{{VCPUs: 1, RAM: 1024, Disk: 2}, {VCPUs: 1, RAM: 1024, Disk: 2}},
+				PublicVIP: true,
+				From:      now.Add(-time.Hour),
+				To:        now,
+			})
+
+			gomega.Expect(record.VmUuid).To(gomega.Equal("lb-id"))
+			gomega.Expect(record.MachineName).To(gomega.Equal("lb"))
+			gomega.Expect(record.LocalGroupId.Value).To(gomega.Equal("project"))
+			gomega.Expect(record.Status.Value).To(gomega.Equal("ACTIVE"))
+			gomega.Expect(record.StartTime.Seconds).To(gomega.Equal(now.Add(-time.Hour).Unix()))
+			gomega.Expect(record.EndTime.Seconds).To(gomega.Equal(now.Unix()))
+			gomega.Expect(record.WallDuration.Seconds).To(gomega.Equal(int64(3600)))
+			gomega.Expect(record.CpuDuration.Seconds).To(gomega.Equal(int64(2 * 3600)))
+			gomega.Expect(record.CpuCount).To(gomega.Equal(uint32(2)))
+			gomega.Expect(record.Memory.Value).To(gomega.Equal(uint64(2048)))
+			gomega.Expect(record.Disk.Value).To(gomega.Equal(uint64(4)))
+			gomega.Expect(record.PublicIpCount.Value).To(gomega.Equal(uint64(1)))
+		})
+
+		ginkgo.It("should not contain public IPs and sizes without a public VIP and amphorae", func() {
+			record := preparer.record(&Resource{LoadBalancer: &loadbalancers.LoadBalancer{ID: "lb-id"}, From: now, To: now})
+
+			gomega.Expect(record.PublicIpCount).To(gomega.BeNil())
+			gomega.Expect(record.Memory).To(gomega.BeNil())
+			gomega.Expect(record.Disk).To(gomega.BeNil())
+			gomega.Expect(record.CpuCount).To(gomega.BeZero())
+		})
+	})
+})
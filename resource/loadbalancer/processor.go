@@ -0,0 +1,208 @@
+package loadbalancer
+
+import (
+	"net"
+	"sync"
+
+	"github.com/goat-project/goat-os/auth"
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/reader"
+	"github.com/goat-project/goat-os/resource"
+	"github.com/goat-project/goat-os/resource/server"
+	"github.com/goat-project/goat-os/util"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/amphorae"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Processor to process load balancer's data.
+type Processor struct {
+	reader reader.Reader
+
+	amphoraeOnce sync.Once
+	amphorae     map[string][]amphorae.Amphora
+}
+
+// CreateProcessor creates processor with reader.
+func CreateProcessor(r *reader.Reader) *Processor {
+	if r == nil {
+		log.WithFields(log.Fields{}).Error(constants.ErrCreateProcReaderNil)
+		return nil
+	}
+
+	return &Processor{
+		reader: *r,
+	}
+}
+
+// Reader gets reader.
+func (p *Processor) Reader() *reader.Reader {
+	return &p.reader
+}
+
+// Process provides listing of the load balancers of the project with flavors of their amphora servers
+// and floating IPs of their VIPs.
+func (p *Processor) Process(project projects.Project, osClient *gophercloud.ProviderClient, read chan resource.Resource,
+	wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	lbClient, err := auth.CreateLoadBalancerV2ServiceClient(osClient)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("unable to create Load Balancer V2 service client")
+		return
+	}
+
+	lbs, err := listLoadBalancers(reader.CreateReader(lbClient), project.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": project.ID}).Error("error list load balancers")
+		return
+	}
+
+	if len(lbs) == 0 {
+		return // the project does not have any load balancer
+	}
+
+	amps := p.listAmphorae(lbClient)
+
+	fipPorts := make(map[string]bool)
+	if nClient, err := auth.CreateNetworkV2ServiceClient(osClient); err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("unable to create Network V2 service client")
+	} else {
+		fipPorts = floatingIPPorts(reader.CreateReader(nClient), project.ID)
+	}
+
+	cClient, err := auth.CreateComputeV2EmbeddedFlavorServiceClient(osClient)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("unable to create Compute V2 service client")
+		return
+	}
+
+	computeReader := reader.CreateReader(cClient)
+
+	for i := range lbs {
+		read <- &Resource{
+			Project:      &project,
+			LoadBalancer: &lbs[i],
+			Flavors:      amphoraFlavors(computeReader, amps[lbs[i].ID]),
+			PublicVIP:    publicVIP(&lbs[i], fipPorts),
+		}
+	}
+}
+
+func listLoadBalancers(r *reader.Reader, projectID string) ([]loadbalancers.LoadBalancer, error) {
+	lbs, err := r.ListLoadBalancers(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := lbs.AllPages()
+	if err != nil {
+		return nil, err
+	}
+
+	return loadbalancers.ExtractLoadBalancers(pages)
+}
+
+// listAmphorae returns amphorae by their load balancers, the amphorae are read only once. They are visible
+// only to administrators.
+func (p *Processor) listAmphorae(client *gophercloud.ServiceClient) map[string][]amphorae.Amphora {
+	p.amphoraeOnce.Do(func() {
+		p.amphorae = make(map[string][]amphorae.Amphora)
+
+		r, err := reader.CreateReader(client).ListAmphorae()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error list amphorae")
+			return
+		}
+
+		pages, err := r.AllPages()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error get amphora pages")
+			return
+		}
+
+		amps, err := amphorae.ExtractAmphorae(pages)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error extract amphorae")
+			return
+		}
+
+		for _, amphora := range amps {
+			p.amphorae[amphora.LoadbalancerID] = append(p.amphorae[amphora.LoadbalancerID], amphora)
+		}
+	})
+
+	return p.amphorae
+}
+
+// floatingIPPorts returns IDs of ports with floating IPs of the project.
+func floatingIPPorts(r *reader.Reader, projectID string) map[string]bool {
+	ports := make(map[string]bool)
+
+	fipsPager, err := r.ListFloatingIPs(projectID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": projectID}).Error("error list floating ips")
+		return ports
+	}
+
+	pages, err := fipsPager.AllPages()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": projectID}).Error("error get floating ip pages")
+		return ports
+	}
+
+	fips, err := floatingips.ExtractFloatingIPs(pages)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": projectID}).Error("error extract floating ips")
+		return ports
+	}
+
+	for _, fip := range fips {
+		if fip.PortID != "" {
+			ports[fip.PortID] = true
+		}
+	}
+
+	return ports
+}
+
+// publicVIP returns whether the VIP of the load balancer is a public IPv4 address or its port has a floating IP.
+func publicVIP(lb *loadbalancers.LoadBalancer, fipPorts map[string]bool) bool {
+	return util.IsPublicIPv4(net.ParseIP(lb.VipAddress)) || fipPorts[lb.VipPortID]
+}
+
+// amphoraFlavors returns flavors of servers of the amphorae.
+func amphoraFlavors(r *reader.Reader, amps []amphorae.Amphora) []*flavors.Flavor {
+	var f []*flavors.Flavor
+
+	for _, amphora := range amps {
+		if amphora.ComputeID == "" {
+			continue // the amphora does not have any server yet
+		}
+
+		rslt, err := r.GetServer(amphora.ComputeID)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "id": amphora.ComputeID}).Error("error get amphora server")
+			continue
+		}
+
+		s, err := rslt.(servers.GetResult).Extract()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "id": amphora.ComputeID}).Error("error extract amphora server")
+			continue
+		}
+
+		if flavor := server.EmbeddedFlavor(s); flavor != nil {
+			f = append(f, flavor)
+		}
+	}
+
+	return f
+}
@@ -0,0 +1,103 @@
+package loadbalancer
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/goat-project/goat-os/reader"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/amphorae"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Load Balancer Processor tests", func() {
+	var (
+		server *httptest.Server
+		client *gophercloud.ServiceClient
+	)
+
+	ginkgo.BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/octavia/amphorae", func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"amphorae": [{"id": "amp-1", "loadbalancer_id": "lb-1", "compute_id": "server-1"},
+				{"id": "amp-2", "loadbalancer_id": "lb-1", "compute_id": "server-2"},
+				{"id": "amp-3", "loadbalancer_id": "lb-2"}]}`))
+		})
+		mux.HandleFunc("/floatingips", func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"floatingips": [{"id": "fip-1", "port_id": "vip-port"},
+				{"id": "fip-2", "port_id": null}]}`))
+		})
+		mux.HandleFunc("/servers/server-1", func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"server": {"id": "server-1",
+				"flavor": {"vcpus": 1, "ram": 1024, "disk": 2, "ephemeral": 1, "original_name": "amphora"}}}`))
+		})
+		mux.HandleFunc("/servers/server-2", func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		server = httptest.NewServer(mux)
+
+		client = &gophercloud.ServiceClient{
+			ProviderClient: &gophercloud.ProviderClient{TokenID: "token"},
+			Endpoint:       server.URL + "/",
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+	})
+
+	ginkgo.Describe("amphorae", func() {
+		ginkgo.It("should group the amphorae by their load balancers", func() {
+			amps := (&Processor{}).listAmphorae(client)
+
+			gomega.Expect(amps).To(gomega.HaveLen(2))
+			gomega.Expect(amps["lb-1"]).To(gomega.HaveLen(2))
+			gomega.Expect(amps["lb-2"][0].ID).To(gomega.Equal("amp-3"))
+		})
+
+		ginkgo.It("should return flavors of the amphora servers which can be read", func() {
+			f := amphoraFlavors(reader.CreateReader(client), []amphorae.Amphora{
+				{ID: "amp-1", ComputeID: "server-1"},
+				{ID: "amp-2", ComputeID: "server-2"},
+				{ID: "amp-3"},
+			})
+
+			gomega.Expect(f).To(gomega.Equal([]*flavors.Flavor{
+				{Name: "amphora", VCPUs: 1, RAM: 1024, Disk: 2, Ephemeral: 1},
+			}))
+		})
+	})
+
+	ginkgo.Describe("public VIP", func() {
+		ginkgo.It("should read ports with floating IPs", func() {
+			gomega.Expect(floatingIPPorts(reader.CreateReader(client), "project")).To(
+				gomega.Equal(map[string]bool{"vip-port": true}))
+		})
+
+		ginkgo.It("should detect a VIP with a floating IP", func() {
+			lb := &loadbalancers.LoadBalancer{VipAddress: "10.0.0.5", VipPortID: "vip-port"}
+
+			gomega.Expect(publicVIP(lb, map[string]bool{"vip-port": true})).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should detect a public VIP address", func() {
+			lb := &loadbalancers.LoadBalancer{VipAddress: "147.251.0.5", VipPortID: "vip-port"}
+
+			gomega.Expect(publicVIP(lb, map[string]bool{})).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should not detect a private VIP address without a floating IP", func() {
+			lb := &loadbalancers.LoadBalancer{VipAddress: "10.0.0.5", VipPortID: "vip-port"}
+
+			gomega.Expect(publicVIP(lb, map[string]bool{"other-port": true})).To(gomega.BeFalse())
+		})
+	})
+})
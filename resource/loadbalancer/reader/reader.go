@@ -0,0 +1,27 @@
+package reader
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/amphorae"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// LoadBalancers structure for a Reader which reads an array of load balancers of a project.
+type LoadBalancers struct {
+	ProjectID string
+}
+
+// ReadResources reads load balancers.
+func (lb *LoadBalancers) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return loadbalancers.List(client, loadbalancers.ListOpts{ProjectID: lb.ProjectID})
+}
+
+// Amphorae structure for a Reader which reads an array of amphorae of all load balancers.
+type Amphorae struct {
+}
+
+// ReadResources reads amphorae, they are visible only to administrators.
+func (a *Amphorae) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return amphorae.List(client, amphorae.ListOpts{})
+}
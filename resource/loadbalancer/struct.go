@@ -0,0 +1,25 @@
+package loadbalancer
+
+import (
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+)
+
+// Resource represents "Load Balancer Resource" with information about project, Octavia load balancer,
+// flavors of its amphora servers, whether its VIP has a public IP and the period the load balancer is accounted for.
+type Resource struct {
+	Project      *projects.Project
+	LoadBalancer *loadbalancers.LoadBalancer
+	Flavors      []*flavors.Flavor
+	PublicVIP    bool
+	From         time.Time
+	To           time.Time
+}
+
+// UnmarshalJSON function to implement Resource interface.
+func (lb *Resource) UnmarshalJSON(b []byte) error {
+	return lb.Project.UnmarshalJSON(b)
+}
@@ -2,20 +2,31 @@ package network
 
 import (
 	"sync"
+	"time"
 
+	"github.com/goat-project/goat-os/filter"
 	"github.com/goat-project/goat-os/resource"
+
+	log "github.com/sirupsen/logrus"
 )
 
-// Filter to filter network data.
+// Filter contains times from/to of the period the IPs are accounted for.
 type Filter struct {
+	recordsFrom time.Time
+	recordsTo   time.Time
 }
 
 // CreateFilter creates Filter.
 func CreateFilter() *Filter {
-	return &Filter{}
+	recordsFrom, recordsTo := filter.Period()
+
+	return &Filter{
+		recordsFrom: recordsFrom,
+		recordsTo:   recordsTo,
+	}
 }
 
-// Filtering - only for VM relevant.
+// Filtering sets the accounted period of the project and writes it to filtered channel.
 func (f *Filter) Filtering(network resource.Resource, filtered chan resource.Resource, wg *sync.WaitGroup) {
 	defer wg.Done()
 
@@ -23,5 +34,14 @@ func (f *Filter) Filtering(network resource.Resource, filtered chan resource.Res
 		return
 	}
 
-	filtered <- network
+	netUser, ok := network.(*NetUser)
+	if !ok {
+		log.WithFields(log.Fields{"err": "no network user"}).Error("error filter network")
+		return
+	}
+
+	netUser.From = f.recordsFrom
+	netUser.To = f.recordsTo
+
+	filtered <- netUser
 }
@@ -7,8 +7,8 @@ import (
 	"github.com/goat-project/goat-os/resource"
 	"github.com/goat-project/goat-os/resource/network"
 
-	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 
 	"github.com/onsi/ginkgo"
 	"github.com/onsi/gomega"
@@ -0,0 +1,243 @@
+package network
+
+import (
+	"math"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// modes of counting IPs of a project in the filtered period
+const (
+	countCurrent = "current"
+	countPeak    = "peak"
+	countAverage = "average"
+)
+
+// IP types of ip records
+const (
+	ipv4 = "IPv4"
+	ipv6 = "IPv6"
+)
+
+// interval during which an address of a public IP resource (floating IP, port or router gateway)
+// was held by a project, the IP is attributed to the user it was attributed to when last seen
+type interval struct {
+	Address string    `json:"address"`
+	User    string    `json:"user,omitempty"`
+	First   time.Time `json:"first"`
+	Last    time.Time `json:"last"`
+}
+
+// history of public IPs of projects which is stored in the state file between runs,
+// intervals of a project are stored by resource id and address
+type history struct {
+	mu       sync.Mutex
+	Projects map[string]map[string]*interval `json:"projects"`
+}
+
+// observed public IP with a key of the resource and address, user and creation time, if known
+type observed struct {
+	key     string
+	address string
+	user    string
+	created time.Time
+}
+
+func createHistory() *history {
+	return &history{Projects: make(map[string]map[string]*interval)}
+}
+
+// observe records addresses of the project seen at time now and returns all intervals of the project
+// which end in the period from. The start of an address seen for the first time is its creation time,
+// or time from, when the creation time is not known. Addresses created after time now are not observed
+// and an interval is never shortened, so accounting of a past period (backfill) does not change the history
+// of later periods.
+func (h *history) observe(projectID string, addresses []observed, from, now time.Time) []interval {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	project, ok := h.Projects[projectID]
+	if !ok {
+		project = make(map[string]*interval)
+		h.Projects[projectID] = project
+	}
+
+	for _, a := range addresses {
+		if a.created.After(now) {
+			continue // the address did not exist at time now
+		}
+
+		i, ok := project[a.key]
+		if !ok {
+			first := a.created
+			if first.IsZero() {
+				first = from
+			}
+
+			if first.IsZero() || first.After(now) {
+				first = now
+			}
+
+			i = &interval{Address: a.address, First: first}
+			project[a.key] = i
+		}
+
+		if !a.created.IsZero() && a.created.Before(i.First) {
+			i.First = a.created // the address was first seen in a later period
+		}
+
+		if !now.Before(i.Last) {
+			i.User = a.user // the user is the one the address is attributed to when last seen
+			i.Last = now
+		}
+	}
+
+	var intervals []interval
+
+	for key, i := range project {
+		if i.Last.Before(from) {
+			delete(project, key) // released before the period, it will never be accounted again
+			continue
+		}
+
+		intervals = append(intervals, *i)
+	}
+
+	return intervals
+}
+
+// ipCount returns number of IPs held in the period from-to according to the mode. The current mode counts
+// addresses seen at time now, the peak mode the maximum of addresses held at the same time and the average
+// mode the IP time divided by the length of the period.
+func ipCount(intervals []interval, from, to, now time.Time, mode string) uint32 {
+	switch mode {
+	case countCurrent:
+		var count uint32
+
+		for _, i := range intervals {
+			if !i.First.After(now) && !i.Last.Before(now) {
+				count++
+			}
+		}
+
+		return count
+	case countAverage:
+		if from.IsZero() {
+			from = earliest(intervals)
+		}
+
+		if !to.After(from) {
+			return peak(intervals, from, to)
+		}
+
+		var held time.Duration
+
+		for _, i := range intervals {
+			start, end := clip(i, from, to)
+			if end.After(start) {
+				held += end.Sub(start)
+			}
+		}
+
+		return uint32(math.Round(float64(held) / float64(to.Sub(from))))
+	default:
+		return peak(intervals, from, to)
+	}
+}
+
+func peak(intervals []interval, from, to time.Time) uint32 {
+	type event struct {
+		t     time.Time
+		delta int
+	}
+
+	var events []event
+
+	for _, i := range intervals {
+		start, end := clip(i, from, to)
+		if start.After(end) {
+			continue
+		}
+
+		events = append(events, event{start, 1}, event{end, -1})
+	}
+
+	// an address released at the same time as another one is taken counts as held at the same time
+	sort.Slice(events, func(a, b int) bool {
+		if events[a].t.Equal(events[b].t) {
+			return events[a].delta > events[b].delta
+		}
+
+		return events[a].t.Before(events[b].t)
+	})
+
+	var count, max int
+
+	for _, e := range events {
+		count += e.delta
+		if count > max {
+			max = count
+		}
+	}
+
+	return uint32(max)
+}
+
+func clip(i interval, from, to time.Time) (time.Time, time.Time) {
+	start, end := i.First, i.Last
+
+	if start.Before(from) {
+		start = from
+	}
+
+	if end.After(to) {
+		end = to
+	}
+
+	return start, end
+}
+
+func earliest(intervals []interval) time.Time {
+	var first time.Time
+
+	for _, i := range intervals {
+		if first.IsZero() || i.First.Before(first) {
+			first = i.First
+		}
+	}
+
+	return first
+}
+
+// byUser splits intervals by users the IPs are attributed to.
+func byUser(intervals []interval) map[string][]interval {
+	users := make(map[string][]interval)
+
+	for _, i := range intervals {
+		users[i.User] = append(users[i.User], i)
+	}
+
+	return users
+}
+
+// byIPType splits intervals to IPv4 and IPv6 intervals.
+func byIPType(intervals []interval) map[string][]interval {
+	types := make(map[string][]interval)
+
+	for _, i := range intervals {
+		ip := net.ParseIP(i.Address)
+		if ip == nil {
+			continue
+		}
+
+		if ip.To4() != nil {
+			types[ipv4] = append(types[ipv4], i)
+		} else {
+			types[ipv6] = append(types[ipv6], i)
+		}
+	}
+
+	return types
+}
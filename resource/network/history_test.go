@@ -0,0 +1,89 @@
+package network
+
+import (
+	"time"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Network History tests", func() {
+	var (
+		from = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		to   = time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC)
+		h    *history
+	)
+
+	ginkgo.BeforeEach(func() {
+		h = createHistory()
+	})
+
+	ginkgo.Describe("observe addresses", func() {
+		ginkgo.Context("when a floating ip is seen for the first time", func() {
+			ginkgo.It("should be held from its creation time", func() {
+				created := from.Add(24 * time.Hour)
+
+				intervals := h.observe("p", []observed{{key: "1/1.2.3.4", address: "1.2.3.4", created: created}},
+					from, to)
+
+				gomega.Expect(intervals).To(gomega.Equal([]interval{{Address: "1.2.3.4", First: created, Last: to}}))
+			})
+		})
+
+		ginkgo.Context("when an ip without creation time is seen for the first time", func() {
+			ginkgo.It("should be held from the start of the period", func() {
+				intervals := h.observe("p", []observed{{key: "port/1.2.3.4", address: "1.2.3.4"}}, from, to)
+
+				gomega.Expect(intervals).To(gomega.Equal([]interval{{Address: "1.2.3.4", First: from, Last: to}}))
+			})
+		})
+
+		ginkgo.Context("when a past period is accounted after a later one", func() {
+			ginkgo.It("should not move the end of the interval backwards", func() {
+				a := observed{key: "1/1.2.3.4", address: "1.2.3.4", user: "user-2", created: from}
+				h.observe("p", []observed{a}, from, to)
+
+				a.user = "user-1"
+				intervals := h.observe("p", []observed{a}, from, from.Add(24*time.Hour))
+
+				gomega.Expect(intervals).To(gomega.Equal([]interval{{Address: "1.2.3.4", User: "user-2", First: from,
+					Last: to}}))
+			})
+
+			ginkgo.It("should skip ips created after the period", func() {
+				intervals := h.observe("p", []observed{{key: "1/1.2.3.4", address: "1.2.3.4",
+					created: to.Add(time.Hour)}}, from, to)
+
+				gomega.Expect(intervals).To(gomega.BeEmpty())
+			})
+		})
+
+		ginkgo.Context("when an ip was released before the period", func() {
+			ginkgo.It("should be forgotten", func() {
+				h.observe("p", []observed{{key: "port/1.2.3.4", address: "1.2.3.4"}}, time.Time{}, from.Add(-time.Hour))
+
+				gomega.Expect(h.observe("p", nil, from, to)).To(gomega.BeEmpty())
+			})
+		})
+	})
+
+	ginkgo.Describe("count ips", func() {
+		// one ip held for 29 days and released, another one held during the last 10 days
+		intervals := []interval{
+			{Address: "1.2.3.4", First: from, Last: from.Add(29 * 24 * time.Hour)},
+			{Address: "1.2.3.5", First: to.Add(-10 * 24 * time.Hour), Last: to},
+		}
+
+		ginkgo.It("should count ips held at the end of the period in the current mode", func() {
+			gomega.Expect(ipCount(intervals, from, to, to, countCurrent)).To(gomega.Equal(uint32(1)))
+		})
+
+		ginkgo.It("should count ips held at the same time in the peak mode", func() {
+			gomega.Expect(ipCount(intervals, from, to, to, countPeak)).To(gomega.Equal(uint32(2)))
+		})
+
+		ginkgo.It("should count ip time divided by the period in the average mode", func() {
+			gomega.Expect(ipCount(intervals, from, to, to, countAverage)).To(gomega.Equal(uint32(1)))
+		})
+	})
+})
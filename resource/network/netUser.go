@@ -1,14 +1,25 @@
 package network
 
 import (
-	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/floatingips"
+	"time"
+
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 )
 
-// NetUser represents "Resource" with information about project and his floating ips.
+// NetUser represents "Resource" with information about project and his public IPs - floating IPs,
+// ports with IPs assigned directly on public networks and routers with gateway on public networks.
+// Users contains ids of users which the floating IPs and ports are attributed to by their id.
 type NetUser struct {
 	Project     *projects.Project
 	FloatingIPs []floatingips.FloatingIP
+	Ports       []ports.Port
+	Routers     []routers.Router
+	Users       map[string]string
+	From        time.Time
+	To          time.Time
 }
 
 // UnmarshalJSON function to implement Resource interface.
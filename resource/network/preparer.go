@@ -1,13 +1,17 @@
 package network
 
 import (
-	"net"
 	"sync"
 	"time"
 
+	"github.com/goat-project/goat-os/config"
 	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/initialize"
+	"github.com/goat-project/goat-os/reader"
 	"github.com/goat-project/goat-os/resource"
+	"github.com/goat-project/goat-os/state"
 	"github.com/goat-project/goat-os/util"
+	"github.com/goat-project/goat-os/vo"
 	"github.com/goat-project/goat-os/writer"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
@@ -24,11 +28,22 @@ import (
 
 // Preparer to prepare network data to specific structure for writing to Goat server.
 type Preparer struct {
-	Writer writer.Writer
+	identityReader reader.Reader
+	Writer         writer.Writer
+	userIdentity   map[string]string
+	history        *history
+	statePath      string
+	countMode      string
+	vo             *vo.Mapper
 }
 
 // CreatePreparer creates Preparer for network records.
-func CreatePreparer(limiter *rate.Limiter, conn *grpc.ClientConn) *Preparer {
+func CreatePreparer(ir *reader.Reader, limiter *rate.Limiter, conn *grpc.ClientConn) *Preparer {
+	if ir == nil {
+		log.WithFields(log.Fields{}).Error(constants.ErrCreatePrepReaderNil)
+		return nil
+	}
+
 	if limiter == nil {
 		log.WithFields(log.Fields{}).Error(constants.ErrCreatePrepLimiterNil)
 		return nil
@@ -39,14 +54,50 @@ func CreatePreparer(limiter *rate.Limiter, conn *grpc.ClientConn) *Preparer {
 		return nil
 	}
 
+	countMode := viper.GetString(constants.CfgNetworkIPCount)
+	if countMode == "" {
+		countMode = countPeak
+	}
+
+	if countMode != countCurrent && countMode != countPeak && countMode != countAverage {
+		log.WithFields(log.Fields{"ip-count": countMode}).Error("unknown ip count mode, peak is used")
+		countMode = countPeak
+	}
+
 	return &Preparer{
-		Writer: *writer.CreateWriter(CreateWriter(limiter), conn),
+		identityReader: *ir,
+		Writer:         *writer.CreateWriter(CreateWriter(limiter), conn),
+		history:        createHistory(),
+		statePath:      viper.GetString(constants.CfgNetworkStatePath),
+		countMode:      countMode,
+		vo:             vo.CreateMapper(ir),
 	}
 }
 
-// InitializeMaps - only for VM relevant.
+// InitializeMaps reads user identities and loads history of public IPs from the state file.
 func (p *Preparer) InitializeMaps(wg *sync.WaitGroup) {
 	defer wg.Done()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		p.userIdentity = initialize.UserIdentity(p.identityReader)
+		if p.userIdentity == nil {
+			log.WithFields(log.Fields{"error": "map is empty"}).Error("error create user identity map")
+		}
+	}()
+
+	if p.statePath == "" {
+		return
+	}
+
+	if err := state.Load(p.statePath, p.history); err != nil {
+		log.WithFields(log.Fields{"error": err, "path": p.statePath}).Error("error load network state")
+	}
+
+	if p.history.Projects == nil {
+		p.history.Projects = make(map[string]map[string]*interval)
+	}
 }
 
 // Preparation prepares network data for writing and call method to write.
@@ -59,21 +110,24 @@ func (p *Preparer) Preparation(acc resource.Resource, wg *sync.WaitGroup) {
 		return
 	}
 
-	countIPv4, countIPv6 := countIPs(*netUser)
-
-	if countIPv4 != 0 {
-		ipv4Record := createIPRecord(*netUser, "IPv4", countIPv4)
-
-		if err := p.Writer.Write(ipv4Record); err != nil {
-			log.WithFields(log.Fields{"error": err, "id": netUser.Project.ID}).Error(constants.ErrPrepWrite)
-		}
+	// the addresses are measured at the end of the period, the period ends now by default
+	to := netUser.To
+	if to.IsZero() {
+		to = time.Now()
 	}
 
-	if countIPv6 != 0 {
-		ipv6Record := createIPRecord(*netUser, "IPv6", countIPv6)
+	intervals := p.history.observe(netUser.Project.ID, observedAddresses(*netUser), netUser.From, to)
+
+	for user, userIntervals := range byUser(intervals) {
+		for ipType, typeIntervals := range byIPType(userIntervals) {
+			count := ipCount(typeIntervals, netUser.From, to, to, p.countMode)
+			if count == 0 {
+				continue
+			}
 
-		if err := p.Writer.Write(ipv6Record); err != nil {
-			log.WithFields(log.Fields{"error": err, "id": netUser.Project.ID}).Error(constants.ErrPrepWrite)
+			if err := p.Writer.Write(p.createIPRecord(*netUser, user, ipType, count, to)); err != nil {
+				log.WithFields(log.Fields{"error": err, "id": netUser.Project.ID}).Error(constants.ErrPrepWrite)
+			}
 		}
 	}
 }
@@ -88,11 +142,21 @@ func (p *Preparer) SendIdentifier() error {
 func (p *Preparer) Finish() {
 	p.Writer.Finish()
 
+	if p.statePath != "" {
+		p.history.mu.Lock()
+		err := state.Save(p.statePath, p.history)
+		p.history.mu.Unlock()
+
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "path": p.statePath}).Error("error save network state")
+		}
+	}
+
 	log.WithFields(log.Fields{"type": "network"}).Debug("finished")
 }
 
 func getSiteName() string {
-	siteName := viper.GetString(constants.CfgNetworkSiteName)
+	siteName := config.SiteName(config.Network)
 	if siteName == "" {
 		log.WithFields(log.Fields{}).Error(constants.ErrNoSiteName) // should never happen
 	}
@@ -101,11 +165,11 @@ func getSiteName() string {
 }
 
 func getCloudComputeService() *wrappers.StringValue {
-	return util.WrapStr(viper.GetString(constants.CfgNetworkCloudComputeService))
+	return util.WrapStr(config.CloudComputeService(config.Network))
 }
 
 func getCloudType() string {
-	ct := viper.GetString(constants.CfgNetworkCloudType)
+	ct := config.CloudType(config.Network)
 	if ct == "" {
 		log.WithFields(log.Fields{}).Error(constants.ErrNoCloudType) // should never happen
 	}
@@ -113,36 +177,58 @@ func getCloudType() string {
 	return ct
 }
 
-func countIPs(user NetUser) (uint32, uint32) {
-	var countIPv4 uint32
-	var countIPv6 uint32
+// observedAddresses returns floating IPs, IPs of ports on public networks and IPs of router gateways.
+func observedAddresses(user NetUser) []observed {
+	var addresses []observed
 
 	for _, fip := range user.FloatingIPs {
-		ip := net.ParseIP(fip.IP)
-		if ip == nil {
-			continue
+		addresses = append(addresses, observed{key: fip.ID + "/" + fip.FloatingIP, address: fip.FloatingIP,
+			user: user.Users[fip.ID], created: fip.CreatedAt})
+	}
+
+	for _, port := range user.Ports {
+		for _, fixedIP := range port.FixedIPs {
+			addresses = append(addresses, observed{key: port.ID + "/" + fixedIP.IPAddress, address: fixedIP.IPAddress,
+				user: user.Users[port.ID]})
 		}
+	}
 
-		if ip4 := ip.To4(); ip4 != nil {
-			countIPv4++
-		} else if ip6 := ip.To16(); ip6 != nil {
-			countIPv6++
+	for _, router := range user.Routers {
+		for _, fixedIP := range router.GatewayInfo.ExternalFixedIPs {
+			addresses = append(addresses, observed{key: router.ID + "/" + fixedIP.IPAddress,
+				address: fixedIP.IPAddress})
 		}
 	}
 
-	return countIPv4, countIPv6
+	return addresses
 }
 
-func createIPRecord(netUser NetUser, ipType string, ipCount uint32) *pb.IpRecord {
+// createIPRecord creates ip record of the user. IPs which are not attributed to any user
+// (router gateways, floating IPs not associated with a server) are accounted to the project.
+func (p *Preparer) createIPRecord(netUser NetUser, user, ipType string, ipCount uint32,
+	measurementTime time.Time) *pb.IpRecord {
+	localUser := netUser.Project.ID
+	globalUserName := netUser.Project.Name
+	fqan := vo.LegacyFqan(netUser.Project.DomainID)
+
+	if p.vo.Enabled() {
+		fqan = p.vo.Map(netUser.Project, user).Fqan
+	}
+
+	if user != "" {
+		localUser = user
+		globalUserName = p.userIdentity[user]
+	}
+
 	return &pb.IpRecord{
-		MeasurementTime:     &timestamp.Timestamp{Seconds: time.Now().Unix()},
+		MeasurementTime:     &timestamp.Timestamp{Seconds: measurementTime.Unix()},
 		SiteName:            getSiteName(),
 		CloudComputeService: getCloudComputeService(),
 		CloudType:           getCloudType(),
-		LocalUser:           netUser.Project.ID,
+		LocalUser:           localUser,
 		LocalGroup:          netUser.Project.DomainID,
-		GlobalUserName:      netUser.Project.Name,
-		Fqan:                "/" + netUser.Project.DomainID + "/Role=NULL/Capability=NULL",
+		GlobalUserName:      globalUserName,
+		Fqan:                fqan,
 		IpType:              ipType,
 		IpCount:             ipCount,
 	}
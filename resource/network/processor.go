@@ -1,26 +1,41 @@
 package network
 
 import (
+	"strings"
 	"sync"
 
-	"github.com/goat-project/goat-os/constants"
-
 	"github.com/goat-project/goat-os/auth"
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/reader"
+	"github.com/goat-project/goat-os/resource"
+	"github.com/goat-project/goat-os/util"
 
 	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/external"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 
-	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/floatingips"
-
-	"github.com/goat-project/goat-os/reader"
-	"github.com/goat-project/goat-os/resource"
+	"github.com/spf13/viper"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// ports owned by network services (dhcp, router gateways and interfaces, floating ips) do not represent
+// public IPs used by a project, router gateways are accounted from routers
+const networkDeviceOwnerPrefix = "network:"
+
 // Processor to process network data.
 type Processor struct {
-	reader reader.Reader
+	reader        reader.Reader
+	networkReader reader.Reader
+	computeReader reader.Reader
+
+	publicMu       sync.Mutex
+	publicNetworks map[string]bool
 }
 
 // CreateProcessor creates Processor to manage reading from Openstack.
@@ -41,42 +56,249 @@ func (p *Processor) Reader() *reader.Reader {
 }
 
 func (p *Processor) createReader(osClient *gophercloud.ProviderClient) {
+	nClient, err := auth.CreateNetworkV2ServiceClient(osClient)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("unable to create Network V2 service client")
+		return
+	}
+
+	p.networkReader = *reader.CreateReader(nClient)
+
 	cClient, err := auth.CreateComputeV2ServiceClient(osClient)
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Error("unable to create Compute V2 service client")
 		return
 	}
 
-	p.reader = *reader.CreateReader(cClient)
+	p.computeReader = *reader.CreateReader(cClient)
 }
 
-// Process provides listing of the users.
+// Process provides listing of the floating IPs, ports and routers of the project which have public IPs.
+// Floating IPs and ports are attributed to users of the servers they are associated with.
 func (p *Processor) Process(project projects.Project, osClient *gophercloud.ProviderClient, read chan resource.Resource,
 	wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	p.createReader(osClient)
 
-	fips, err := p.reader.ListFloatingIPs()
+	public, err := p.publicNetworkIDs()
 	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("error retrieve floating Ips")
+		log.WithFields(log.Fields{"error": err, "project": project.ID}).Error("error list public networks")
 		return
 	}
 
-	pages, err := fips.AllPages() // todo add openstack pagination and wg
+	fips, err := p.listFloatingIPs(project.ID)
 	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("error get fip pages")
+		log.WithFields(log.Fields{"error": err, "project": project.ID}).Error("error list floating ips")
 		return
 	}
 
-	floatings, err := floatingips.ExtractFloatingIPs(pages)
+	prts, err := p.listPorts(project.ID)
 	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("error extract floating ips")
+		log.WithFields(log.Fields{"error": err, "project": project.ID}).Error("error list ports")
 		return
 	}
 
+	rtrs, err := p.listRouters(project.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": project.ID}).Error("error list routers")
+		return
+	}
+
+	servs, err := p.listServers(project.ID)
+	if err != nil {
+		// public IPs are still accounted, but attributed to the project only
+		log.WithFields(log.Fields{"error": err, "project": project.ID}).Error("error list servers")
+	}
+
 	read <- &NetUser{
 		Project:     &project,
-		FloatingIPs: floatings,
+		FloatingIPs: publicFloatingIPs(fips, public),
+		Ports:       publicPorts(prts, public),
+		Routers:     publicRouters(rtrs, public),
+		Users:       users(fips, prts, servs),
 	}
 }
+
+// publicNetworkIDs returns ids of the networks with public IPs. The networks are read only once since they
+// are shared by all projects, a failed reading is not cached, so it is repeated for the next project.
+func (p *Processor) publicNetworkIDs() (map[string]bool, error) {
+	p.publicMu.Lock()
+	defer p.publicMu.Unlock()
+
+	if p.publicNetworks == nil {
+		public, err := p.listPublicNetworks()
+		if err != nil {
+			return nil, err
+		}
+
+		p.publicNetworks = public
+	}
+
+	return p.publicNetworks, nil
+}
+
+// listPublicNetworks returns ids of the networks with public IPs. These are the networks configured
+// by id or name or all external networks when no network is configured.
+func (p *Processor) listPublicNetworks() (map[string]bool, error) {
+	configured := viper.GetStringSlice(constants.CfgNetworkPublicNetworks)
+
+	nets, err := p.networkReader.ListNetworks()
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := nets.AllPages()
+	if err != nil {
+		return nil, err
+	}
+
+	var allNetworks []struct {
+		networks.Network
+		external.NetworkExternalExt
+	}
+
+	if err = networks.ExtractNetworksInto(pages, &allNetworks); err != nil {
+		return nil, err
+	}
+
+	public := make(map[string]bool)
+
+	for _, n := range allNetworks {
+		if len(configured) > 0 {
+			if util.Contains(configured, n.ID) || util.Contains(configured, n.Name) {
+				public[n.ID] = true
+			}
+		} else if n.External {
+			public[n.ID] = true
+		}
+	}
+
+	log.WithFields(log.Fields{"networks": public}).Debug("public networks")
+
+	return public, nil
+}
+
+func (p *Processor) listFloatingIPs(projectID string) ([]floatingips.FloatingIP, error) {
+	fips, err := p.networkReader.ListFloatingIPs(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := fips.AllPages() // todo add openstack pagination and wg
+	if err != nil {
+		return nil, err
+	}
+
+	return floatingips.ExtractFloatingIPs(pages)
+}
+
+func (p *Processor) listPorts(projectID string) ([]ports.Port, error) {
+	prts, err := p.networkReader.ListPorts(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := prts.AllPages() // todo add openstack pagination and wg
+	if err != nil {
+		return nil, err
+	}
+
+	return ports.ExtractPorts(pages)
+}
+
+func (p *Processor) listRouters(projectID string) ([]routers.Router, error) {
+	rtrs, err := p.networkReader.ListRouters(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := rtrs.AllPages() // todo add openstack pagination and wg
+	if err != nil {
+		return nil, err
+	}
+
+	return routers.ExtractRouters(pages)
+}
+
+func (p *Processor) listServers(projectID string) ([]servers.Server, error) {
+	servs, err := p.computeReader.ListAllServers(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := servs.AllPages() // todo add openstack pagination and wg
+	if err != nil {
+		return nil, err
+	}
+
+	return servers.ExtractServers(pages)
+}
+
+// users returns ids of users of the servers which the floating IPs and ports are associated with
+// by floating IP or port id. A floating IP is associated with a server through its port.
+func users(fips []floatingips.FloatingIP, prts []ports.Port, servs []servers.Server) map[string]string {
+	serverUsers := make(map[string]string)
+	for _, server := range servs {
+		serverUsers[server.ID] = server.UserID
+	}
+
+	portUsers := make(map[string]string)
+	for _, port := range prts {
+		if user, ok := serverUsers[port.DeviceID]; ok && user != "" {
+			portUsers[port.ID] = user
+		}
+	}
+
+	result := make(map[string]string)
+
+	for id, user := range portUsers {
+		result[id] = user
+	}
+
+	for _, fip := range fips {
+		if user, ok := portUsers[fip.PortID]; ok {
+			result[fip.ID] = user
+		}
+	}
+
+	return result
+}
+
+func publicFloatingIPs(fips []floatingips.FloatingIP, public map[string]bool) []floatingips.FloatingIP {
+	var result []floatingips.FloatingIP
+
+	for _, fip := range fips {
+		if public[fip.FloatingNetworkID] {
+			result = append(result, fip)
+		}
+	}
+
+	return result
+}
+
+// publicPorts returns ports with IPs assigned directly on public (provider) networks.
+func publicPorts(prts []ports.Port, public map[string]bool) []ports.Port {
+	var result []ports.Port
+
+	for _, port := range prts {
+		if public[port.NetworkID] && !strings.HasPrefix(port.DeviceOwner, networkDeviceOwnerPrefix) {
+			result = append(result, port)
+		}
+	}
+
+	return result
+}
+
+// publicRouters returns routers with external gateway on public networks.
+func publicRouters(rtrs []routers.Router, public map[string]bool) []routers.Router {
+	var result []routers.Router
+
+	for _, router := range rtrs {
+		if public[router.GatewayInfo.NetworkID] {
+			result = append(result, router)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,83 @@
+package network
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/goat-project/goat-os/reader"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Network Processor tests", func() {
+	ginkgo.Describe("attribute public IPs to users", func() {
+		servs := []servers.Server{{ID: "server-1", UserID: "user-1"}, {ID: "server-2", UserID: "user-2"}}
+		prts := []ports.Port{
+			{ID: "port-1", DeviceID: "server-1", DeviceOwner: "compute:nova"},
+			{ID: "port-2", DeviceID: "server-2", DeviceOwner: "compute:nova"},
+			{ID: "port-3", DeviceID: "router-1", DeviceOwner: "network:router_gateway"},
+		}
+		fips := []floatingips.FloatingIP{{ID: "fip-1", PortID: "port-1"}, {ID: "fip-2"}}
+
+		ginkgo.It("should attribute floating IPs to the user of the associated server", func() {
+			u := users(fips, prts, servs)
+
+			gomega.Expect(u).To(gomega.HaveKeyWithValue("fip-1", "user-1"))
+			gomega.Expect(u).NotTo(gomega.HaveKey("fip-2"))
+		})
+
+		ginkgo.It("should attribute ports to the user of the server", func() {
+			u := users(fips, prts, servs)
+
+			gomega.Expect(u).To(gomega.HaveKeyWithValue("port-2", "user-2"))
+			gomega.Expect(u).NotTo(gomega.HaveKey("port-3"))
+		})
+	})
+
+	ginkgo.Describe("public networks", func() {
+		var (
+			server   *httptest.Server
+			failures int
+			proc     *Processor
+		)
+
+		ginkgo.BeforeEach(func() {
+			failures = 1
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if failures > 0 {
+					failures--
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"networks": [{"id": "net-public", "router:external": true},
+					{"id": "net-private", "router:external": false}]}`))
+			}))
+
+			proc = &Processor{networkReader: *reader.CreateReader(&gophercloud.ServiceClient{
+				ProviderClient: &gophercloud.ProviderClient{TokenID: "token"},
+				Endpoint:       server.URL + "/",
+			})}
+		})
+
+		ginkgo.AfterEach(func() {
+			server.Close()
+		})
+
+		ginkgo.It("should not cache a failed reading of the networks", func() {
+			_, err := proc.publicNetworkIDs()
+			gomega.Expect(err).To(gomega.HaveOccurred())
+
+			public, err := proc.publicNetworkIDs()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(public).To(gomega.Equal(map[string]bool{"net-public": true}))
+		})
+	})
+})
@@ -2,15 +2,48 @@ package reader
 
 import (
 	"github.com/gophercloud/gophercloud"
-	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/pagination"
 )
 
-// FloatingIP structure for a Reader which read floating IPs by tenant id.
+// FloatingIP structure for a Reader which read floating IPs by project id.
 type FloatingIP struct {
+	ProjectID string
 }
 
-// ReadResources reads a server info.
+// ReadResources reads floating IPs of the project.
 func (r *FloatingIP) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
-	return floatingips.List(client)
+	return floatingips.List(client, floatingips.ListOpts{ProjectID: r.ProjectID})
+}
+
+// Port structure for a Reader which read ports by project id.
+type Port struct {
+	ProjectID string
+}
+
+// ReadResources reads ports of the project.
+func (r *Port) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return ports.List(client, ports.ListOpts{ProjectID: r.ProjectID})
+}
+
+// Router structure for a Reader which read routers by project id.
+type Router struct {
+	ProjectID string
+}
+
+// ReadResources reads routers of the project.
+func (r *Router) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return routers.List(client, routers.ListOpts{ProjectID: r.ProjectID})
+}
+
+// Network structure for a Reader which read all networks.
+type Network struct {
+}
+
+// ReadResources reads networks.
+func (r *Network) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return networks.List(client, networks.ListOpts{})
 }
@@ -0,0 +1,16 @@
+package resource
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/baremetal/v1/nodes"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// NodesReader structure for a Reader which read an array of bare metal nodes with details.
+type NodesReader struct {
+}
+
+// ReadResources reads an array of bare metal nodes with details.
+func (nr *NodesReader) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return nodes.ListDetail(client, nil)
+}
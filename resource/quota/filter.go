@@ -0,0 +1,90 @@
+package quota
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/filter"
+	"github.com/goat-project/goat-os/resource"
+
+	"github.com/karrick/tparse/v2"
+
+	"github.com/spf13/viper"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// length of the period quotas are accounted for when neither quota period nor records-for-period is set
+const defaultPeriod = "1h"
+
+// Filter contains times from/to of the filtered period and length of the period quotas are accounted for.
+type Filter struct {
+	recordsFrom time.Time
+	recordsTo   time.Time
+	period      string
+}
+
+// CreateFilter creates Filter. The length of the period is the quota period, records-for-period or one hour.
+func CreateFilter() *Filter {
+	recordsFrom, recordsTo := filter.Period()
+
+	period := viper.GetString(constants.CfgQuotaPeriod)
+	if period == "" {
+		period = viper.GetString(constants.CfgRecordsForPeriod)
+	}
+
+	if period == "" {
+		period = defaultPeriod
+	}
+
+	if _, err := tparse.AddDuration(time.Time{}, "-"+period); err != nil {
+		log.WithFields(log.Fields{"error": err, "period": period}).Error("wrong format of quota period")
+		period = defaultPeriod
+	}
+
+	return &Filter{
+		recordsFrom: recordsFrom,
+		recordsTo:   recordsTo,
+		period:      period,
+	}
+}
+
+// Filtering sets the accounted period to the quotas and writes them to filtered channel. Quotas are known only
+// when they are read, so they are accounted for the period before the reading clipped to the filtered period.
+// Quotas read outside of the filtered period, e.g. when a past period is accounted, are filtered out.
+func (f *Filter) Filtering(res resource.Resource, filtered chan resource.Resource, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if res == nil {
+		log.WithFields(log.Fields{"err": "no quota"}).Error("error filter empty quota")
+		return
+	}
+
+	q := res.(*Resource)
+
+	from, err := tparse.AddDuration(q.Observed, "-"+f.period)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "period": f.period}).Error("wrong format of quota period")
+		return
+	}
+
+	if from.Before(f.recordsFrom) {
+		from = f.recordsFrom
+	}
+
+	to := q.Observed
+	if to.After(f.recordsTo) {
+		to = f.recordsTo
+	}
+
+	if !to.After(from) {
+		log.WithFields(log.Fields{"project": q.Project.ID, "observed": q.Observed}).Debug(
+			"quota not observed in the filtered period")
+		return
+	}
+
+	q.From, q.To = from, to
+
+	filtered <- q
+}
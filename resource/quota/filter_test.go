@@ -0,0 +1,87 @@
+package quota
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/resource"
+
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+
+	"github.com/spf13/viper"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Quota Filter tests", func() {
+	var (
+		q        *Resource
+		observed time.Time
+		wg       sync.WaitGroup
+	)
+
+	filtering := func() chan resource.Resource {
+		filtered := make(chan resource.Resource, 1)
+
+		wg.Add(1)
+		CreateFilter().Filtering(q, filtered, &wg)
+		wg.Wait()
+
+		return filtered
+	}
+
+	ginkgo.BeforeEach(func() {
+		observed = time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+		q = &Resource{Project: &projects.Project{ID: "project-id"}, Observed: observed}
+
+		viper.Set(constants.CfgRecordsFrom, observed.Add(-24*time.Hour))
+		viper.Set(constants.CfgRecordsTo, observed.Add(time.Minute))
+	})
+
+	ginkgo.AfterEach(func() {
+		viper.Reset()
+	})
+
+	ginkgo.Describe("filter quota", func() {
+		ginkgo.It("should account the quota for an hour before its reading by default", func() {
+			gomega.Expect(filtering()).To(gomega.Receive(gomega.Equal(q)))
+			gomega.Expect(q.From).To(gomega.Equal(observed.Add(-time.Hour)))
+			gomega.Expect(q.To).To(gomega.Equal(observed))
+		})
+
+		ginkgo.It("should account the quota for the quota period", func() {
+			viper.Set(constants.CfgQuotaPeriod, "6h")
+
+			gomega.Expect(filtering()).To(gomega.Receive(gomega.Equal(q)))
+			gomega.Expect(q.From).To(gomega.Equal(observed.Add(-6 * time.Hour)))
+		})
+
+		ginkgo.It("should clip the period to the filtered period", func() {
+			viper.Set(constants.CfgRecordsFrom, observed.Add(-10*time.Minute))
+			viper.Set(constants.CfgRecordsTo, observed.Add(-5*time.Minute))
+
+			gomega.Expect(filtering()).To(gomega.Receive(gomega.Equal(q)))
+			gomega.Expect(q.From).To(gomega.Equal(observed.Add(-10 * time.Minute)))
+			gomega.Expect(q.To).To(gomega.Equal(observed.Add(-5 * time.Minute)))
+		})
+
+		ginkgo.It("should not account the quota for a past period", func() {
+			viper.Set(constants.CfgRecordsFrom, observed.Add(-90*24*time.Hour))
+			viper.Set(constants.CfgRecordsTo, observed.Add(-60*24*time.Hour))
+
+			gomega.Expect(filtering()).NotTo(gomega.Receive())
+		})
+
+		ginkgo.It("should not send anything to the channel when the quota is nil", func() {
+			filtered := make(chan resource.Resource, 1)
+
+			wg.Add(1)
+			CreateFilter().Filtering(nil, filtered, &wg)
+			wg.Wait()
+
+			gomega.Expect(filtered).NotTo(gomega.Receive())
+		})
+	})
+})
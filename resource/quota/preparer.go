@@ -0,0 +1,253 @@
+package quota
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/goat-project/goat-os/config"
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/reader"
+	"github.com/goat-project/goat-os/resource"
+	"github.com/goat-project/goat-os/resource/network"
+	"github.com/goat-project/goat-os/resource/server"
+	"github.com/goat-project/goat-os/resource/storage"
+	"github.com/goat-project/goat-os/util"
+	"github.com/goat-project/goat-os/vo"
+	"github.com/goat-project/goat-os/writer"
+
+	"github.com/spf13/viper"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	pb "github.com/goat-project/goat-proto-go"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	volumeShare      = "volume-quota"
+	swiftShare       = "swift-quota"
+	floatingIPType   = "IPv4"
+	roleAttribute    = "role"
+	gigabyte         = 1024 * 1024 * 1024
+	recordIDTemplate = "quota-%s-%d"
+	// storage records of a project differ by their share
+	storageRecordIDTemplate = recordIDTemplate + "-%s"
+)
+
+// Preparer to prepare quotas to records of reserved capacity for writing to Goat server. Virtual machine records
+// carry reserved cores and RAM, storage records reserved volume and object storage capacity and IP records
+// reserved floating IPs.
+type Preparer struct {
+	Writer writer.Writer
+	vo     *vo.Mapper
+	kind   string
+}
+
+// CreatePreparer creates Preparer for quota records of the kind, see CreateProcessor.
+func CreatePreparer(ir *reader.Reader, limiter *rate.Limiter, conn *grpc.ClientConn, kind string) *Preparer {
+	if ir == nil {
+		log.WithFields(log.Fields{}).Error(constants.ErrCreatePrepReaderNil)
+		return nil
+	}
+
+	if limiter == nil {
+		log.WithFields(log.Fields{}).Error(constants.ErrCreatePrepLimiterNil)
+		return nil
+	}
+
+	if conn == nil {
+		log.WithFields(log.Fields{}).Error(constants.ErrCreatePrepConnNil)
+		return nil
+	}
+
+	var w *writer.Writer
+	switch kind {
+	case KindCompute:
+		w = writer.CreateWriter(server.CreateWriter(limiter), conn)
+	case KindStorage:
+		w = writer.CreateWriter(storage.CreateWriter(limiter), conn)
+	case KindNetwork:
+		w = writer.CreateWriter(network.CreateWriter(limiter), conn)
+	default:
+		log.WithFields(log.Fields{"kind": kind}).Error("error unknown kind of quota")
+		return nil
+	}
+
+	return &Preparer{
+		Writer: *w,
+		vo:     vo.CreateMapper(ir),
+		kind:   kind,
+	}
+}
+
+// InitializeMaps does not read any additional data, quotas belong to projects.
+func (p *Preparer) InitializeMaps(wg *sync.WaitGroup) {
+	defer wg.Done()
+}
+
+// Preparation prepares records of capacity reserved by quotas of the project and call method to write them.
+// Unlimited and zero quotas do not reserve any capacity and are not written.
+func (p *Preparer) Preparation(acc resource.Resource, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	q := acc.(*Resource)
+	if q == nil || q.Project == nil {
+		log.WithFields(log.Fields{"error": "empty quota"}).Error(constants.ErrPrepEmptyQuota)
+		return
+	}
+
+	for _, record := range p.records(q) {
+		if err := p.Writer.Write(record); err != nil {
+			log.WithFields(log.Fields{"error": err, "project": q.Project.ID}).Error(constants.ErrPrepWrite)
+		}
+	}
+}
+
+// SendIdentifier sends identifier to Goat server.
+func (p *Preparer) SendIdentifier() error {
+	return p.Writer.SendIdentifier()
+}
+
+// Finish gets to know to the Goat server that a writing is finished and a response is expected.
+// Then, it closes the gRPC connection.
+func (p *Preparer) Finish() {
+	p.Writer.Finish()
+
+	log.WithFields(log.Fields{"type": "quota", "kind": p.kind}).Debug("finished")
+}
+
+func (p *Preparer) records(q *Resource) []writer.Record {
+	var records []writer.Record
+
+	switch p.kind {
+	case KindCompute:
+		if q.Cores > 0 || q.RAM > 0 {
+			records = append(records, p.vmRecord(q))
+		}
+	case KindStorage:
+		if q.Gigabytes > 0 {
+			records = append(records, p.storageRecord(q, volumeShare, uint64(q.Gigabytes)*gigabyte))
+		}
+
+		if q.SwiftBytes > 0 {
+			records = append(records, p.storageRecord(q, swiftShare, uint64(q.SwiftBytes)))
+		}
+	case KindNetwork:
+		if q.FloatingIPs > 0 {
+			records = append(records, p.ipRecord(q))
+		}
+	}
+
+	return records
+}
+
+// vmRecord returns virtual machine record of cores and RAM reserved for the project during the accounted period.
+// The record is identified by the project and start of the period, so repeated accounting of the same period
+// updates the record.
+func (p *Preparer) vmRecord(q *Resource) *pb.VmRecord {
+	wallDuration := q.To.Unix() - q.From.Unix()
+	if wallDuration < 0 {
+		wallDuration = 0
+	}
+
+	record := &pb.VmRecord{
+		VmUuid:              fmt.Sprintf(recordIDTemplate, q.Project.ID, q.From.Unix()),
+		SiteName:            getSiteName(),
+		CloudComputeService: util.WrapStr(config.CloudComputeService(config.Quota)),
+		MachineName:         q.Project.Name,
+		LocalUserId:         util.WrapStr(q.Project.ID),
+		LocalGroupId:        util.WrapStr(q.Project.ID),
+		Fqan:                util.WrapStr(p.getFqan(q)),
+		StartTime:           util.WrapTime(&q.From),
+		EndTime:             util.WrapTime(&q.To),
+		WallDuration:        &duration.Duration{Seconds: wallDuration},
+		CloudType:           util.WrapStr(getCloudType()),
+	}
+
+	if q.Cores > 0 {
+		record.CpuCount = uint32(q.Cores)
+		record.CpuDuration = &duration.Duration{Seconds: wallDuration * int64(q.Cores)}
+	}
+
+	if q.RAM > 0 {
+		record.Memory = &wrappers.UInt64Value{Value: uint64(q.RAM)}
+	}
+
+	return record
+}
+
+// storageRecord returns storage record of capacity (bytes) reserved for the project during the accounted period.
+func (p *Preparer) storageRecord(q *Resource, share string, allocated uint64) *pb.StorageRecord {
+	record := &pb.StorageRecord{
+		RecordID:                  fmt.Sprintf(storageRecordIDTemplate, q.Project.ID, q.From.Unix(), share),
+		CreateTime:                &timestamp.Timestamp{Seconds: q.To.Unix()},
+		StorageSystem:             viper.GetString(constants.CfgOpenstackIdentityEndpoint),
+		Site:                      util.WrapStr(getSiteName()),
+		StorageShare:              util.WrapStr(share),
+		StorageMedia:              &wrappers.StringValue{Value: "disk"},
+		LocalUser:                 util.WrapStr(q.Project.ID),
+		LocalGroup:                util.WrapStr(q.Project.ID),
+		UserIdentity:              util.WrapStr(q.Project.Name),
+		Group:                     util.WrapStr(q.Project.Name),
+		StartTime:                 &timestamp.Timestamp{Seconds: q.From.Unix()},
+		EndTime:                   &timestamp.Timestamp{Seconds: q.To.Unix()},
+		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: allocated},
+	}
+
+	if p.vo.Enabled() {
+		group := p.vo.Map(q.Project, "")
+		record.Group = util.WrapStr(group.VO)
+		record.GroupAttribute = util.WrapStr(group.Role)
+		record.GroupAttributeType = util.WrapStr(roleAttribute)
+	}
+
+	return record
+}
+
+// ipRecord returns IP record of floating IPs reserved for the project at the end of the accounted period.
+func (p *Preparer) ipRecord(q *Resource) *pb.IpRecord {
+	return &pb.IpRecord{
+		MeasurementTime:     &timestamp.Timestamp{Seconds: q.To.Unix()},
+		SiteName:            getSiteName(),
+		CloudComputeService: util.WrapStr(config.CloudComputeService(config.Quota)),
+		CloudType:           getCloudType(),
+		LocalUser:           q.Project.ID,
+		LocalGroup:          q.Project.ID,
+		GlobalUserName:      q.Project.Name,
+		Fqan:                p.getFqan(q),
+		IpType:              floatingIPType,
+		IpCount:             uint32(q.FloatingIPs),
+	}
+}
+
+// getFqan returns FQAN of the project mapped to a virtual organization or the FQAN of the project when
+// the mapping is not enabled.
+func (p *Preparer) getFqan(q *Resource) string {
+	if p.vo.Enabled() {
+		return p.vo.Map(q.Project, "").Fqan
+	}
+
+	return vo.LegacyFqan(q.Project.ID)
+}
+
+func getSiteName() string {
+	siteName := config.SiteName(config.Quota)
+	if siteName == "" {
+		log.WithFields(log.Fields{}).Error(constants.ErrNoSiteName) // should never happen
+	}
+
+	return siteName
+}
+
+func getCloudType() string {
+	ct := config.CloudType(config.Quota)
+	if ct == "" {
+		log.WithFields(log.Fields{}).Error(constants.ErrNoCloudType) // should never happen
+	}
+
+	return ct
+}
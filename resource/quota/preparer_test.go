@@ -0,0 +1,113 @@
+package quota
+
+import (
+	"time"
+
+	"github.com/goat-project/goat-os/reader"
+	"github.com/goat-project/goat-os/vo"
+
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+
+	pb "github.com/goat-project/goat-proto-go"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Quota Preparer tests", func() {
+	var q *Resource
+
+	preparer := func(kind string) *Preparer {
+		return &Preparer{vo: vo.CreateMapper(&reader.Reader{}), kind: kind}
+	}
+
+	ginkgo.BeforeEach(func() {
+		to := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+
+		q = &Resource{
+			Project:     &projects.Project{ID: "project-id", Name: "project", DomainID: "domain-id"},
+			From:        to.Add(-time.Hour),
+			To:          to,
+			Cores:       10,
+			RAM:         20480,
+			Gigabytes:   100,
+			SwiftBytes:  1000,
+			FloatingIPs: 2,
+		}
+	})
+
+	ginkgo.Describe("virtual machine records", func() {
+		ginkgo.It("should reserve cores and RAM for the period", func() {
+			records := preparer(KindCompute).records(q)
+			gomega.Expect(records).To(gomega.HaveLen(1))
+
+			record := records[0].(*pb.VmRecord)
+			gomega.Expect(record.VmUuid).To(gomega.Equal("quota-project-id-1609542000"))
+			gomega.Expect(record.CpuCount).To(gomega.Equal(uint32(10)))
+			gomega.Expect(record.Memory.Value).To(gomega.Equal(uint64(20480)))
+			gomega.Expect(record.WallDuration.Seconds).To(gomega.Equal(int64(3600)))
+			gomega.Expect(record.CpuDuration.Seconds).To(gomega.Equal(int64(36000)))
+			gomega.Expect(record.Fqan.Value).To(gomega.Equal("/project-id/Role=NULL/Capability=NULL"))
+		})
+
+		ginkgo.It("should not reserve unlimited cores", func() {
+			q.Cores = unlimited
+
+			record := preparer(KindCompute).records(q)[0].(*pb.VmRecord)
+			gomega.Expect(record.CpuCount).To(gomega.BeZero())
+			gomega.Expect(record.CpuDuration).To(gomega.BeNil())
+			gomega.Expect(record.Memory.Value).To(gomega.Equal(uint64(20480)))
+		})
+
+		ginkgo.It("should not write a record for unlimited cores and RAM", func() {
+			q.Cores, q.RAM = unlimited, unlimited
+
+			gomega.Expect(preparer(KindCompute).records(q)).To(gomega.BeEmpty())
+		})
+	})
+
+	ginkgo.Describe("storage records", func() {
+		ginkgo.It("should reserve volume and object storage capacity", func() {
+			records := preparer(KindStorage).records(q)
+			gomega.Expect(records).To(gomega.HaveLen(2))
+
+			volume := records[0].(*pb.StorageRecord)
+			gomega.Expect(volume.RecordID).To(gomega.Equal("quota-project-id-1609542000-volume-quota"))
+			gomega.Expect(volume.StorageShare.Value).To(gomega.Equal(volumeShare))
+			gomega.Expect(volume.ResourceCapacityAllocated.Value).To(gomega.Equal(uint64(100 * gigabyte)))
+			gomega.Expect(volume.LocalGroup.Value).To(gomega.Equal("project-id"))
+			gomega.Expect(volume.Group.Value).To(gomega.Equal("project"))
+			gomega.Expect(volume.GroupAttribute).To(gomega.BeNil())
+
+			swift := records[1].(*pb.StorageRecord)
+			gomega.Expect(swift.RecordID).To(gomega.Equal("quota-project-id-1609542000-swift-quota"))
+			gomega.Expect(swift.StorageShare.Value).To(gomega.Equal(swiftShare))
+			gomega.Expect(swift.ResourceCapacityAllocated.Value).To(gomega.Equal(uint64(1000)))
+		})
+
+		ginkgo.It("should not write a record for object storage without quota", func() {
+			q.SwiftBytes = 0
+
+			gomega.Expect(preparer(KindStorage).records(q)).To(gomega.HaveLen(1))
+		})
+	})
+
+	ginkgo.Describe("IP records", func() {
+		ginkgo.It("should reserve floating IPs", func() {
+			records := preparer(KindNetwork).records(q)
+			gomega.Expect(records).To(gomega.HaveLen(1))
+
+			record := records[0].(*pb.IpRecord)
+			gomega.Expect(record.IpCount).To(gomega.Equal(uint32(2)))
+			gomega.Expect(record.IpType).To(gomega.Equal(floatingIPType))
+			gomega.Expect(record.LocalGroup).To(gomega.Equal("project-id"))
+			gomega.Expect(record.Fqan).To(gomega.Equal("/project-id/Role=NULL/Capability=NULL"))
+		})
+
+		ginkgo.It("should not write a record for unlimited floating IPs", func() {
+			q.FloatingIPs = unlimited
+
+			gomega.Expect(preparer(KindNetwork).records(q)).To(gomega.BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,164 @@
+package quota
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goat-project/goat-os/auth"
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/reader"
+	"github.com/goat-project/goat-os/resource"
+	"github.com/goat-project/goat-os/resource/storage"
+
+	"github.com/gophercloud/gophercloud"
+	volumeQuotas "github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/quotasets"
+	computeQuotas "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/quotasets"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/quotas"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const unlimited = -1
+
+// kinds of quotas, each kind is read by its own processor and written as its own kind of records
+const (
+	// KindCompute represents quotas of cores and RAM written as virtual machine records
+	KindCompute = "compute"
+	// KindStorage represents quotas of volume gigabytes and object storage bytes written as storage records
+	KindStorage = "storage"
+	// KindNetwork represents quotas of floating IPs written as IP records
+	KindNetwork = "network"
+)
+
+// Processor to process quotas of projects. Each processor reads quotas of one kind, KindCompute, KindStorage
+// or KindNetwork.
+type Processor struct {
+	reader reader.Reader
+	kind   string
+}
+
+// CreateProcessor creates processor with reader for the kind of quotas.
+func CreateProcessor(r *reader.Reader, kind string) *Processor {
+	if r == nil {
+		log.WithFields(log.Fields{}).Error(constants.ErrCreateProcReaderNil)
+		return nil
+	}
+
+	return &Processor{
+		reader: *r,
+		kind:   kind,
+	}
+}
+
+// Reader gets reader.
+func (p *Processor) Reader() *reader.Reader {
+	return &p.reader
+}
+
+// Process provides reading of quotas of the project.
+func (p *Processor) Process(project projects.Project, osClient *gophercloud.ProviderClient, read chan resource.Resource,
+	wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	q := &Resource{
+		Project:     &project,
+		Cores:       unlimited,
+		RAM:         unlimited,
+		Gigabytes:   unlimited,
+		FloatingIPs: unlimited,
+	}
+
+	switch p.kind {
+	case KindCompute:
+		readComputeQuota(osClient, q)
+	case KindStorage:
+		readVolumeQuota(osClient, q)
+		readSwiftQuota(osClient, q)
+	case KindNetwork:
+		readNetworkQuota(osClient, q)
+	default:
+		log.WithFields(log.Fields{"kind": p.kind}).Error("error unknown kind of quota")
+		return
+	}
+
+	q.Observed = time.Now()
+
+	read <- q
+}
+
+func readComputeQuota(osClient *gophercloud.ProviderClient, q *Resource) {
+	client, err := auth.CreateComputeV2ServiceClient(osClient)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("unable to create Compute V2 service client")
+		return
+	}
+
+	rslt, err := reader.CreateReader(client).GetComputeQuota(q.Project.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": q.Project.ID}).Error("error get compute quota")
+		return
+	}
+
+	quotaSet, err := rslt.(computeQuotas.GetResult).Extract()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": q.Project.ID}).Error("error extract compute quota")
+		return
+	}
+
+	q.Cores, q.RAM = quotaSet.Cores, quotaSet.RAM
+}
+
+func readVolumeQuota(osClient *gophercloud.ProviderClient, q *Resource) {
+	client, err := auth.CreateNewBlockStorageV3ServiceClient(osClient)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("unable to create New Block Storage V3 service client")
+		return
+	}
+
+	rslt, err := reader.CreateReader(client).GetVolumeQuota(q.Project.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": q.Project.ID}).Error("error get volume quota")
+		return
+	}
+
+	quotaSet, err := rslt.(volumeQuotas.GetResult).Extract()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": q.Project.ID}).Error("error extract volume quota")
+		return
+	}
+
+	q.Gigabytes = quotaSet.Gigabytes
+}
+
+func readSwiftQuota(osClient *gophercloud.ProviderClient, q *Resource) {
+	client, err := auth.CreateObjectStorageV1ProjectServiceClient(osClient, q.Project.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("unable to create Object Storage V1 service client")
+		return
+	}
+
+	q.SwiftBytes = storage.AccountQuota(reader.CreateReader(client))
+}
+
+func readNetworkQuota(osClient *gophercloud.ProviderClient, q *Resource) {
+	client, err := auth.CreateNetworkV2ServiceClient(osClient)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("unable to create Network V2 service client")
+		return
+	}
+
+	rslt, err := reader.CreateReader(client).GetNetworkQuota(q.Project.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": q.Project.ID}).Error("error get network quota")
+		return
+	}
+
+	quota, err := rslt.(quotas.GetResult).Extract()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": q.Project.ID}).Error("error extract network quota")
+		return
+	}
+
+	q.FloatingIPs = quota.FloatingIP
+}
@@ -0,0 +1,40 @@
+package reader
+
+import (
+	"github.com/goat-project/goat-os/result"
+
+	"github.com/gophercloud/gophercloud"
+	volumeQuotas "github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/quotasets"
+	computeQuotas "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/quotasets"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/quotas"
+)
+
+// ComputeQuota structure for a Reader which reads compute quota of a project.
+type ComputeQuota struct {
+	ProjectID string
+}
+
+// ReadResource reads compute quota.
+func (cq *ComputeQuota) ReadResource(client *gophercloud.ServiceClient) result.Result {
+	return computeQuotas.Get(client, cq.ProjectID)
+}
+
+// VolumeQuota structure for a Reader which reads block storage quota of a project.
+type VolumeQuota struct {
+	ProjectID string
+}
+
+// ReadResource reads block storage quota.
+func (vq *VolumeQuota) ReadResource(client *gophercloud.ServiceClient) result.Result {
+	return volumeQuotas.Get(client, vq.ProjectID)
+}
+
+// NetworkQuota structure for a Reader which reads network quota of a project.
+type NetworkQuota struct {
+	ProjectID string
+}
+
+// ReadResource reads network quota.
+func (nq *NetworkQuota) ReadResource(client *gophercloud.ServiceClient) result.Result {
+	return quotas.Get(client, nq.ProjectID)
+}
@@ -0,0 +1,28 @@
+package quota
+
+import (
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+)
+
+// Resource represents "Quota Resource" with information about project, its quotas, the time they were read
+// and the accounted period. Quotas which were not read or are unlimited are set to -1, object storage without
+// quota has 0 bytes.
+type Resource struct {
+	Project  *projects.Project
+	Observed time.Time
+	From     time.Time
+	To       time.Time
+
+	Cores       int
+	RAM         int
+	Gigabytes   int
+	SwiftBytes  int64
+	FloatingIPs int
+}
+
+// UnmarshalJSON function to implement Resource interface.
+func (q *Resource) UnmarshalJSON(b []byte) error {
+	return q.Project.UnmarshalJSON(b)
+}
@@ -0,0 +1,28 @@
+package resource
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/roles"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// RolesReader structure for a Reader which read an array of roles.
+type RolesReader struct {
+}
+
+// RoleAssignmentsReader structure for a Reader which read effective role assignments in a project.
+type RoleAssignmentsReader struct {
+	ProjectID string
+}
+
+// ReadResources reads an array of roles.
+func (rr *RolesReader) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return roles.List(client, roles.ListOpts{})
+}
+
+// ReadResources reads an array of effective role assignments in a project.
+func (rr *RoleAssignmentsReader) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	effective := true
+
+	return roles.ListAssignments(client, roles.ListAssignmentsOpts{ScopeProjectID: rr.ProjectID, Effective: &effective})
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/goat-project/goat-os/auth"
+	"github.com/goat-project/goat-os/reader"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/baremetal/v1/nodes"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// hypervisor type of bare metal (Ironic) nodes
+const bareMetalHypervisorType = "ironic"
+
+// suffix of the default cloud type of bare metal servers
+const bareMetalCloudTypeSuffix = "-bare-metal"
+
+// prefix of extra specs of flavors which request a custom resource class of bare metal nodes
+const customResourcePrefix = "resources:CUSTOM_"
+
+// Node represents size of a bare metal node which a server runs on.
+type Node struct {
+	ID     string
+	CPUs   int
+	Memory int // MB
+	Disk   int // GB
+}
+
+// isBareMetal returns true when the server runs on a bare metal node. It is given by the hypervisor type,
+// if known, otherwise by a custom resource class requested by the flavor of the server.
+func isBareMetal(location Location, extraSpecs map[string]string) bool {
+	if location.HypervisorType != "" {
+		return strings.EqualFold(location.HypervisorType, bareMetalHypervisorType)
+	}
+
+	for key, value := range extraSpecs {
+		if strings.HasPrefix(key, customResourcePrefix) && value != "0" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bareMetalNodes returns bare metal nodes by UUID of servers which they host, the nodes are read only once.
+func (p *Processor) bareMetalNodes(osClient *gophercloud.ProviderClient) map[string]*Node {
+	p.nodesOnce.Do(func() {
+		p.nodes = make(map[string]*Node)
+
+		client, err := auth.CreateBareMetalV1ServiceClient(osClient)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("unable to create Bare Metal V1 service client")
+			return
+		}
+
+		r, err := reader.CreateReader(client).ListBareMetalNodes()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error list bare metal nodes")
+			return
+		}
+
+		pages, err := r.AllPages()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error get bare metal node pages")
+			return
+		}
+
+		n, err := nodes.ExtractNodes(pages)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error extract bare metal nodes")
+			return
+		}
+
+		for i := range n {
+			if n[i].InstanceUUID != "" {
+				p.nodes[n[i].InstanceUUID] = createNode(&n[i])
+			}
+		}
+	})
+
+	return p.nodes
+}
+
+// createNode creates Node from properties of the bare metal node.
+func createNode(node *nodes.Node) *Node {
+	return &Node{
+		ID:     node.UUID,
+		CPUs:   property(node.Properties, "cpus"),
+		Memory: property(node.Properties, "memory_mb"),
+		Disk:   property(node.Properties, "local_gb"),
+	}
+}
+
+// property returns integer property of the bare metal node, which can be a number or a string.
+func property(properties map[string]interface{}, key string) int {
+	switch value := properties[key].(type) {
+	case float64:
+		return int(value)
+	case string:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "property": key}).Error("error parse bare metal node property")
+		}
+
+		return v
+	}
+
+	return 0
+}
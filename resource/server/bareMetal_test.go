@@ -0,0 +1,35 @@
+package server
+
+import (
+	"github.com/gophercloud/gophercloud/openstack/baremetal/v1/nodes"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Server Bare Metal tests", func() {
+	ginkgo.Describe("is bare metal", func() {
+		ginkgo.It("should detect the server by the hypervisor type", func() {
+			gomega.Expect(isBareMetal(Location{HypervisorType: "ironic"}, nil)).To(gomega.BeTrue())
+			gomega.Expect(isBareMetal(Location{HypervisorType: "QEMU"},
+				map[string]string{"resources:CUSTOM_BAREMETAL": "1"})).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should detect the server by the custom resource class of the flavor", func() {
+			gomega.Expect(isBareMetal(Location{}, map[string]string{"resources:VCPU": "0",
+				"resources:CUSTOM_BAREMETAL": "1"})).To(gomega.BeTrue())
+			gomega.Expect(isBareMetal(Location{}, map[string]string{"resources:CUSTOM_BAREMETAL": "0"})).To(
+				gomega.BeFalse())
+		})
+	})
+
+	ginkgo.Describe("create node", func() {
+		ginkgo.It("should read numeric and string properties of the node", func() {
+			node := &nodes.Node{UUID: "node-id", Properties: map[string]interface{}{"cpus": float64(64),
+				"memory_mb": "262144", "local_gb": float64(1800), "cpu_arch": "x86_64"}}
+
+			gomega.Expect(createNode(node)).To(gomega.Equal(&Node{ID: "node-id", CPUs: 64, Memory: 262144,
+				Disk: 1800}))
+		})
+	})
+})
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+)
+
+// servers not seen for the retention are removed from the history
+const historyRetention = 366 * 24 * time.Hour
+
+// flavor of a server since a time, the flavor changes when the server is resized
+type flavorInterval struct {
+	Since     time.Time `json:"since"`
+	Name      string    `json:"name"`
+	VCPUs     int       `json:"vcpus"`
+	RAM       int       `json:"ram"`
+	Disk      int       `json:"disk"`
+	Ephemeral int       `json:"ephemeral"`
+	Swap      int       `json:"swap"`
+}
+
+// flavors of a server and the time when the server was seen last time
+type serverHistory struct {
+	Seen    time.Time        `json:"seen"`
+	Flavors []flavorInterval `json:"flavors"`
+}
+
+// history of server flavors which is stored in the state file between runs
+type history struct {
+	mu      sync.Mutex
+	Servers map[string]*serverHistory `json:"servers"`
+}
+
+func createHistory() *history {
+	return &history{Servers: make(map[string]*serverHistory)}
+}
+
+// resizeNeeded returns whether the flavor differs from the last recorded flavor of the server, so time
+// of its resize is needed to observe it. The time is resolved by the caller without holding the lock.
+func (h *history) resizeNeeded(serverID string, flavor *flavors.Flavor) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	server, ok := h.Servers[serverID]
+	if !ok || flavor == nil || len(server.Flavors) == 0 {
+		return false
+	}
+
+	return !sameFlavor(server.Flavors[len(server.Flavors)-1], newFlavorInterval(flavor))
+}
+
+// observe records the flavor of the server seen at time now and returns all flavors of the server. The first
+// flavor of the server starts at its creation. When the flavor differs from the last one, the new flavor starts
+// at the time resized or now, if the time of the resize is not known.
+func (h *history) observe(serverID string, flavor *flavors.Flavor, created, now, resized time.Time) []flavorInterval {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	server, ok := h.Servers[serverID]
+	if !ok {
+		server = &serverHistory{}
+		h.Servers[serverID] = server
+	}
+
+	server.Seen = now
+	server.Flavors = withFlavor(server.Flavors, flavor, created, now, resized)
+
+	return append([]flavorInterval(nil), server.Flavors...)
+}
+
+// flavors returns all flavors of the server with the flavor seen at time now like observe, but it does not
+// record them.
+func (h *history) flavors(serverID string, flavor *flavors.Flavor, created, now, resized time.Time) []flavorInterval {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var intervals []flavorInterval
+	if server, ok := h.Servers[serverID]; ok {
+		intervals = append(intervals, server.Flavors...)
+	}
+
+	return withFlavor(intervals, flavor, created, now, resized)
+}
+
+// withFlavor returns the intervals with the flavor appended when it differs from the last one.
+func withFlavor(intervals []flavorInterval, flavor *flavors.Flavor, created, now,
+	resized time.Time) []flavorInterval {
+	if flavor == nil {
+		return intervals
+	}
+
+	current := newFlavorInterval(flavor)
+
+	switch n := len(intervals); {
+	case n == 0:
+		current.Since = created
+		intervals = append(intervals, current)
+	case !sameFlavor(intervals[n-1], current):
+		current.Since = resized
+		if current.Since.IsZero() || current.Since.Before(intervals[n-1].Since) || current.Since.After(now) {
+			current.Since = now
+		}
+
+		intervals = append(intervals, current)
+	}
+
+	return intervals
+}
+
+// prune removes servers which were not seen for the retention before now.
+func (h *history) prune(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for id, server := range h.Servers {
+		if server.Seen.Before(now.Add(-historyRetention)) {
+			delete(h.Servers, id)
+		}
+	}
+}
+
+func sameFlavor(a, b flavorInterval) bool {
+	a.Since, b.Since = time.Time{}, time.Time{}
+	return a == b
+}
+
+// newFlavorInterval returns flavor interval with details of the flavor.
+func newFlavorInterval(flavor *flavors.Flavor) flavorInterval {
+	return flavorInterval{Name: flavor.Name, VCPUs: flavor.VCPUs, RAM: flavor.RAM, Disk: flavor.Disk,
+		Ephemeral: flavor.Ephemeral, Swap: flavor.Swap}
+}
+
+// flavor returns flavor with details of the interval.
+func (i flavorInterval) flavor() *flavors.Flavor {
+	return &flavors.Flavor{Name: i.Name, VCPUs: i.VCPUs, RAM: i.RAM, Disk: i.Disk, Ephemeral: i.Ephemeral,
+		Swap: i.Swap}
+}
+
+// cpuDuration returns sum of durations of the flavor intervals between sTime and eTime multiplied by number
+// of CPUs of the flavors.
+func cpuDuration(intervals []flavorInterval, sTime, eTime *timestamp.Timestamp) *duration.Duration {
+	if sTime == nil || eTime == nil {
+		return nil
+	}
+
+	var seconds int64
+
+	for i, interval := range intervals {
+		start := interval.Since.Unix()
+		if start < sTime.Seconds {
+			start = sTime.Seconds
+		}
+
+		end := eTime.Seconds
+		if i+1 < len(intervals) && intervals[i+1].Since.Unix() < end {
+			end = intervals[i+1].Since.Unix()
+		}
+
+		if end > start {
+			seconds += (end - start) * int64(interval.VCPUs)
+		}
+	}
+
+	return &duration.Duration{Seconds: seconds}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Server History tests", func() {
+	var (
+		h       *history
+		created time.Time
+		small   *flavors.Flavor
+		large   *flavors.Flavor
+	)
+
+	ginkgo.BeforeEach(func() {
+		h = createHistory()
+		created = time.Unix(1000, 0)
+		small = &flavors.Flavor{Name: "small", VCPUs: 1, RAM: 1024}
+		large = &flavors.Flavor{Name: "large", VCPUs: 4, RAM: 4096}
+	})
+
+	ginkgo.Describe("observe flavors", func() {
+		ginkgo.It("should start the first flavor at creation of the server", func() {
+			intervals := h.observe("id", small, created, time.Unix(2000, 0), time.Time{})
+
+			gomega.Expect(intervals).To(gomega.HaveLen(1))
+			gomega.Expect(intervals[0].Since).To(gomega.Equal(created))
+			gomega.Expect(intervals[0].VCPUs).To(gomega.Equal(1))
+		})
+
+		ginkgo.It("should not change the history when the flavor is the same", func() {
+			h.observe("id", small, created, time.Unix(2000, 0), time.Time{})
+			intervals := h.observe("id", small, created, time.Unix(3000, 0), time.Time{})
+
+			gomega.Expect(intervals).To(gomega.HaveLen(1))
+		})
+
+		ginkgo.It("should start the new flavor at the time of the resize", func() {
+			h.observe("id", small, created, time.Unix(2000, 0), time.Time{})
+			intervals := h.observe("id", large, created, time.Unix(3000, 0), time.Unix(2500, 0))
+
+			gomega.Expect(intervals).To(gomega.HaveLen(2))
+			gomega.Expect(intervals[1].Since).To(gomega.Equal(time.Unix(2500, 0)))
+			gomega.Expect(intervals[1].VCPUs).To(gomega.Equal(4))
+		})
+
+		ginkgo.It("should start the new flavor now when the time of the resize is not known", func() {
+			h.observe("id", small, created, time.Unix(2000, 0), time.Time{})
+			intervals := h.observe("id", large, created, time.Unix(3000, 0), time.Time{})
+
+			gomega.Expect(intervals[1].Since).To(gomega.Equal(time.Unix(3000, 0)))
+		})
+
+		ginkgo.It("should need time of the resize only when the flavor changed", func() {
+			gomega.Expect(h.resizeNeeded("id", small)).To(gomega.BeFalse())
+
+			h.observe("id", small, created, time.Unix(2000, 0), time.Time{})
+
+			gomega.Expect(h.resizeNeeded("id", small)).To(gomega.BeFalse())
+			gomega.Expect(h.resizeNeeded("id", nil)).To(gomega.BeFalse())
+			gomega.Expect(h.resizeNeeded("id", large)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should keep the last flavor when the flavor is missing", func() {
+			h.observe("id", small, created, time.Unix(2000, 0), time.Time{})
+			intervals := h.observe("id", nil, created, time.Unix(3000, 0), time.Time{})
+
+			gomega.Expect(intervals).To(gomega.HaveLen(1))
+			gomega.Expect(intervals[0].flavor().VCPUs).To(gomega.Equal(1))
+		})
+	})
+
+	ginkgo.Describe("prune servers", func() {
+		ginkgo.It("should remove servers not seen for the retention", func() {
+			h.observe("old", small, created, created, time.Time{})
+			h.observe("new", small, created, created.Add(historyRetention), time.Time{})
+
+			h.prune(created.Add(historyRetention + time.Hour))
+
+			gomega.Expect(h.Servers).To(gomega.HaveKey("new"))
+			gomega.Expect(h.Servers).NotTo(gomega.HaveKey("old"))
+		})
+	})
+
+	ginkgo.Describe("cpu duration", func() {
+		ginkgo.It("should multiply durations of the flavors by their CPUs", func() {
+			intervals := []flavorInterval{{Since: time.Unix(1000, 0), VCPUs: 1}, {Since: time.Unix(2000, 0), VCPUs: 4}}
+
+			d := cpuDuration(intervals, &timestamp.Timestamp{Seconds: 1500}, &timestamp.Timestamp{Seconds: 3000})
+
+			gomega.Expect(d.Seconds).To(gomega.Equal(int64(500*1 + 1000*4)))
+		})
+
+		ginkgo.It("should return zero duration without flavors", func() {
+			d := cpuDuration(nil, &timestamp.Timestamp{Seconds: 1500}, &timestamp.Timestamp{Seconds: 3000})
+
+			gomega.Expect(d.Seconds).To(gomega.BeZero())
+		})
+	})
+})
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"bytes"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/goat-project/goat-os/benchmark"
+	"github.com/goat-project/goat-os/constants"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/aggregates"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/hypervisors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/spf13/viper"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Location of a server - availability zone, compute host, aggregates of the host and hypervisor type.
+// The host, aggregates and hypervisor type are visible only to administrators.
+type Location struct {
+	AvailabilityZone string
+	Host             string
+	Aggregates       []string
+	HypervisorType   string
+}
+
+// rule assigns site name, cloud compute service and benchmark to servers in the location. Conditions
+// are shell patterns (e.g. compute-gpu-*), an empty condition matches any location.
+type rule struct {
+	AvailabilityZone string `mapstructure:"availability-zone"`
+	Host             string `mapstructure:"host"`
+	Aggregate        string `mapstructure:"aggregate"`
+	HypervisorType   string `mapstructure:"hypervisor-type"`
+
+	SiteName            string  `mapstructure:"site-name"`
+	CloudComputeService string  `mapstructure:"cloud-compute-service"`
+	BenchmarkType       string  `mapstructure:"benchmark-type"`
+	Benchmark           float64 `mapstructure:"benchmark"`
+}
+
+// data available in the cloud compute service template, the service is the cloud compute service assigned
+// to the server
+type serviceData struct {
+	Service string
+	Location
+}
+
+// readServiceTemplate returns template of cloud compute service of records or nil when it is not configured.
+func readServiceTemplate() *template.Template {
+	text := viper.GetString(constants.CfgVMCloudComputeServiceTemplate)
+	if text == "" {
+		return nil
+	}
+
+	t, err := template.New("cloud-compute-service").Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "template": text}).Fatal("error parse cloud compute service template")
+	}
+
+	return t
+}
+
+// renderService returns the cloud compute service with the location rendered by the template.
+func renderService(t *template.Template, service string, location Location) string {
+	var b bytes.Buffer
+
+	if err := t.Execute(&b, serviceData{Service: service, Location: location}); err != nil {
+		log.WithFields(log.Fields{"error": err, "template": t.Name()}).Error("error execute template")
+		return service
+	}
+
+	return b.String()
+}
+
+func readRules() []rule {
+	var rules []rule
+	if err := viper.UnmarshalKey(constants.CfgVMRules, &rules); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error read vm rules")
+	}
+
+	return rules
+}
+
+// matchRule returns the first rule matching the location or nil.
+func matchRule(rules []rule, location Location) *rule {
+	for i := range rules {
+		if rules[i].matches(location) {
+			return &rules[i]
+		}
+	}
+
+	return nil
+}
+
+func (r *rule) matches(location Location) bool {
+	if !match(r.AvailabilityZone, location.AvailabilityZone) || !match(r.Host, location.Host) ||
+		!match(r.HypervisorType, location.HypervisorType) {
+		return false
+	}
+
+	if r.Aggregate == "" {
+		return true
+	}
+
+	for _, aggregate := range location.Aggregates {
+		if match(r.Aggregate, aggregate) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func match(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	matched, err := path.Match(pattern, value)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "pattern": pattern}).Error("error match vm rule")
+	}
+
+	return matched
+}
+
+// benchmark returns benchmark of the rule, the second value is false when the rule does not have any.
+func (r *rule) benchmark() (benchmark.Benchmark, bool) {
+	if r == nil || r.Benchmark <= 0 {
+		return benchmark.Benchmark{}, false
+	}
+
+	b := benchmark.Benchmark{Type: r.BenchmarkType, Value: r.Benchmark}
+	if b.Type == "" {
+		b.Type = benchmark.Type()
+	}
+
+	return b, true
+}
+
+// locations returns locations of servers on the page by server ID without aggregates and hypervisor types.
+func locations(page pagination.Page) map[string]Location {
+	var s []struct {
+		ID               string `json:"id"`
+		AvailabilityZone string `json:"OS-EXT-AZ:availability_zone"`
+		Host             string `json:"OS-EXT-SRV-ATTR:host"`
+	}
+
+	locs := make(map[string]Location)
+
+	if err := servers.ExtractServersInto(page, &s); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error extract server locations")
+		return locs
+	}
+
+	for _, server := range s {
+		locs[server.ID] = Location{AvailabilityZone: server.AvailabilityZone, Host: server.Host}
+	}
+
+	return locs
+}
+
+// hostAggregates returns names of aggregates by their hosts, the aggregates are read only once.
+func (p *Processor) hostAggregates() map[string][]string {
+	p.aggregatesOnce.Do(func() {
+		p.aggregates = make(map[string][]string)
+
+		r, err := p.reader.ListAggregates()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error list aggregates")
+			return
+		}
+
+		pages, err := r.AllPages()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error get aggregate pages")
+			return
+		}
+
+		aggs, err := aggregates.ExtractAggregates(pages)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error extract aggregates")
+			return
+		}
+
+		for _, aggregate := range aggs {
+			for _, host := range aggregate.Hosts {
+				p.aggregates[host] = append(p.aggregates[host], aggregate.Name)
+			}
+		}
+
+		for host := range p.aggregates {
+			sort.Strings(p.aggregates[host])
+		}
+	})
+
+	return p.aggregates
+}
+
+// hypervisorTypes returns types of hypervisors by their compute hosts, the hypervisors are read only once.
+func (p *Processor) hypervisorTypes() map[string]string {
+	p.hypervisorsOnce.Do(func() {
+		p.hypervisors = make(map[string]string)
+
+		r, err := p.reader.ListHypervisors()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error list hypervisors")
+			return
+		}
+
+		pages, err := r.AllPages()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error get hypervisor pages")
+			return
+		}
+
+		hyps, err := hypervisors.ExtractHypervisors(pages)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error extract hypervisors")
+			return
+		}
+
+		for _, hypervisor := range hyps {
+			p.hypervisors[hypervisor.Service.Host] = hypervisor.HypervisorType
+		}
+	})
+
+	return p.hypervisors
+}
+
+// locate returns locations of servers on the page by server ID.
+func (p *Processor) locate(page pagination.Page) map[string]Location {
+	locs := locations(page)
+
+	for id, location := range locs {
+		if location.Host == "" {
+			continue // the host is visible only to administrators
+		}
+
+		location.Aggregates = p.hostAggregates()[location.Host]
+		location.HypervisorType = p.hypervisorTypes()[location.Host]
+		locs[id] = location
+	}
+
+	return locs
+}
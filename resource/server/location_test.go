@@ -0,0 +1,84 @@
+package server
+
+import (
+	"github.com/goat-project/goat-os/benchmark"
+	"github.com/goat-project/goat-os/constants"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"github.com/spf13/viper"
+)
+
+var _ = ginkgo.Describe("Server Location tests", func() {
+	location := Location{AvailabilityZone: "zone-a", Host: "compute-gpu-01", Aggregates: []string{"gpu-v100", "hpc"},
+		HypervisorType: "QEMU"}
+
+	ginkgo.AfterEach(func() {
+		viper.Reset()
+	})
+
+	ginkgo.Describe("match rule", func() {
+		ginkgo.It("should return the first rule matching all conditions", func() {
+			rules := []rule{
+				{AvailabilityZone: "zone-b", SiteName: "b"},
+				{Aggregate: "gpu-*", HypervisorType: "xen", SiteName: "xen"},
+				{Host: "compute-gpu-*", Aggregate: "hpc", SiteName: "gpu"},
+				{SiteName: "any"},
+			}
+
+			gomega.Expect(matchRule(rules, location).SiteName).To(gomega.Equal("gpu"))
+		})
+
+		ginkgo.It("should not return any rule when no rule matches", func() {
+			gomega.Expect(matchRule([]rule{{Aggregate: "cpu"}}, location)).To(gomega.BeNil())
+		})
+	})
+
+	ginkgo.Describe("rule benchmark", func() {
+		ginkgo.It("should use type of the rule", func() {
+			b, ok := (&rule{BenchmarkType: "HS23", Benchmark: 10}).benchmark()
+			gomega.Expect(ok).To(gomega.BeTrue())
+			gomega.Expect(b).To(gomega.Equal(benchmark.Benchmark{Type: "HS23", Value: 10}))
+		})
+
+		ginkgo.It("should use the configured type", func() {
+			viper.Set(constants.CfgBenchmarkType, "HS06")
+
+			b, ok := (&rule{Benchmark: 10}).benchmark()
+			gomega.Expect(ok).To(gomega.BeTrue())
+			gomega.Expect(b).To(gomega.Equal(benchmark.Benchmark{Type: "HS06", Value: 10}))
+		})
+
+		ginkgo.It("should not return any benchmark without the value", func() {
+			_, ok := (&rule{BenchmarkType: "HS23"}).benchmark()
+			gomega.Expect(ok).To(gomega.BeFalse())
+		})
+	})
+
+	ginkgo.Describe("locate", func() {
+		ginkgo.It("should return locations of servers with aggregates and hypervisor types of their hosts", func() {
+			p := &Processor{}
+			p.aggregatesOnce.Do(func() { p.aggregates = map[string][]string{"compute-01": {"hpc"}} })
+			p.hypervisorsOnce.Do(func() { p.hypervisors = map[string]string{"compute-01": "QEMU"} })
+
+			page := servers.ServerPage{LinkedPageBase: pagination.LinkedPageBase{PageResult: pagination.PageResult{
+				Result: gophercloud.Result{Body: map[string]interface{}{"servers": []interface{}{
+					map[string]interface{}{"id": "1", "OS-EXT-AZ:availability_zone": "zone-a",
+						"OS-EXT-SRV-ATTR:host": "compute-01"},
+					map[string]interface{}{"id": "2", "OS-EXT-AZ:availability_zone": "zone-b"},
+				}}},
+			}}}
+
+			gomega.Expect(p.locate(page)).To(gomega.Equal(map[string]Location{
+				"1": {AvailabilityZone: "zone-a", Host: "compute-01", Aggregates: []string{"hpc"},
+					HypervisorType: "QEMU"},
+				"2": {AvailabilityZone: "zone-b"},
+			}))
+		})
+	})
+})
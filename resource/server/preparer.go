@@ -2,16 +2,23 @@ package server
 
 import (
 	"net"
+	"strings"
 	"sync"
+	"text/template"
 	"time"
 
+	"github.com/goat-project/goat-os/benchmark"
+	"github.com/goat-project/goat-os/config"
 	"github.com/goat-project/goat-os/constants"
 	"github.com/goat-project/goat-os/initialize"
 	"github.com/goat-project/goat-os/reader"
 	"github.com/goat-project/goat-os/resource"
+	"github.com/goat-project/goat-os/state"
 	"github.com/goat-project/goat-os/util"
+	"github.com/goat-project/goat-os/vo"
 	"github.com/goat-project/goat-os/writer"
 
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/instanceactions"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 
 	"golang.org/x/time/rate"
@@ -20,18 +27,26 @@ import (
 	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/golang/protobuf/ptypes/wrappers"
-	"github.com/spf13/viper"
 
 	pb "github.com/goat-project/goat-proto-go"
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 )
 
+// instance action which changes flavor of the server
+const resizeAction = "resize"
+
 // Preparer to prepare virtual machine data to specific structure for writing to Goat server.
 type Preparer struct {
 	identityReader reader.Reader
-	computeReader  reader.Reader
 	Writer         writer.Writer
 	userIdentity   map[string]string
+	vo             *vo.Mapper
+	benchmark      *benchmark.Mapper
+	rules          []rule
+	service        *template.Template
+	history        *history
+	statePath      string
 }
 
 // CreatePreparer creates Preparer for virtual machine records.
@@ -58,12 +73,17 @@ func CreatePreparer(ir *reader.Reader, cr *reader.Reader, limiter *rate.Limiter,
 
 	return &Preparer{
 		identityReader: *ir,
-		computeReader:  *cr,
 		Writer:         *writer.CreateWriter(CreateWriter(limiter), conn),
+		vo:             vo.CreateMapper(ir),
+		benchmark:      benchmark.CreateMapper(cr),
+		rules:          readRules(),
+		service:        readServiceTemplate(),
+		history:        createHistory(),
+		statePath:      viper.GetString(constants.CfgVMStatePath),
 	}
 }
 
-// InitializeMaps reads additional data for virtual machine record.
+// InitializeMaps reads user identities and loads history of server flavors from the state file.
 func (p *Preparer) InitializeMaps(wg *sync.WaitGroup) {
 	defer wg.Done()
 
@@ -75,6 +95,18 @@ func (p *Preparer) InitializeMaps(wg *sync.WaitGroup) {
 			log.WithFields(log.Fields{"error": "map is empty"}).Error("error create user identity map")
 		}
 	}()
+
+	if p.statePath == "" {
+		return
+	}
+
+	if err := state.Load(p.statePath, p.history); err != nil {
+		log.WithFields(log.Fields{"error": err, "path": p.statePath}).Error("error load vm state")
+	}
+
+	if p.history.Servers == nil {
+		p.history.Servers = make(map[string]*serverHistory)
+	}
 }
 
 // Preparation prepares virtual machine data for writing and call method to write.
@@ -92,6 +124,12 @@ func (p *Preparer) Preparation(acc resource.Resource, wg *sync.WaitGroup) {
 	eTime := util.WrapTime(&t) // todo get end time
 	wallDuration := getWallDuration(sTime, eTime)
 
+	// the current flavor is the last one from the history, which is known even if the flavor is not available now
+	intervals := p.history.observe(server.Server.ID, server.Flavor, server.Server.Created, t, server.Resized)
+	if len(intervals) > 0 {
+		server.Flavor = intervals[len(intervals)-1].flavor()
+	}
+
 	var cpuCount uint32
 	var memory *wrappers.UInt64Value
 	var diskSize *wrappers.UInt64Value
@@ -104,27 +142,45 @@ func (p *Preparer) Preparation(acc resource.Resource, wg *sync.WaitGroup) {
 			memory = &wrappers.UInt64Value{Value: mem}
 		}
 
-		disk := uint64(server.Flavor.Disk)
-		if disk != 0 {
-			diskSize = &wrappers.UInt64Value{Value: disk}
+	}
+
+	cpuDur := cpuDuration(intervals, sTime, eTime)
+
+	// flavors of bare metal nodes do not have real size of the nodes, properties missing in the node are
+	// taken from the flavor
+	if server.Node != nil && server.Node.CPUs > 0 {
+		cpuCount = uint32(server.Node.CPUs)
+
+		if wallDuration != nil {
+			cpuDur = &duration.Duration{Seconds: wallDuration.Seconds * int64(server.Node.CPUs)}
 		}
 	}
 
+	if server.Node != nil && server.Node.Memory > 0 {
+		memory = &wrappers.UInt64Value{Value: uint64(server.Node.Memory)}
+	}
+
+	if disk := getDiskSize(server); disk != 0 {
+		diskSize = &wrappers.UInt64Value{Value: disk}
+	}
+
+	r := matchRule(p.rules, server.Location)
+
 	serverRecord := pb.VmRecord{
 		VmUuid:              server.Server.ID,
-		SiteName:            getSiteName(),
-		CloudComputeService: getCloudComputeService(),
+		SiteName:            getSiteName(r),
+		CloudComputeService: p.getCloudComputeService(r, server),
 		MachineName:         server.Server.Name,
 		LocalUserId:         util.WrapStr(server.Server.UserID),
 		LocalGroupId:        util.WrapStr(server.Server.TenantID),
 		GlobalUserName:      getGlobalUserName(p, server.Server),
-		Fqan:                getFqan(server.Server.TenantID),
+		Fqan:                p.getFqan(server),
 		Status:              util.WrapStr(server.Server.Status),
 		StartTime:           sTime,
 		EndTime:             eTime,
 		SuspendDuration:     getSuspendDuration(sTime, eTime, wallDuration),
 		WallDuration:        wallDuration,
-		CpuDuration:         getCPUDuration(wallDuration, cpuCount),
+		CpuDuration:         cpuDur,
 		CpuCount:            cpuCount,
 		NetworkType:         nil,
 		NetworkInbound:      nil, // todo?
@@ -132,11 +188,14 @@ func (p *Preparer) Preparation(acc resource.Resource, wg *sync.WaitGroup) {
 		PublicIpCount:       getPublicIPCount(server.Server),
 		Memory:              memory,
 		Disk:                diskSize,
-		BenchmarkType:       nil, // todo?
-		Benchmark:           nil, // todo?
-		StorageRecordId:     nil,
+		StorageRecordId:     getStorageRecordID(server),
 		ImageId:             getImageID(server.Server),
-		CloudType:           getCloudType(),
+		CloudType:           getCloudType(server),
+	}
+
+	if b, ok := p.getBenchmark(server, r); ok {
+		serverRecord.BenchmarkType = util.WrapStr(b.Type)
+		serverRecord.Benchmark = &wrappers.FloatValue{Value: float32(b.Value)}
 	}
 
 	if err := p.Writer.Write(&serverRecord); err != nil {
@@ -154,11 +213,87 @@ func (p *Preparer) SendIdentifier() error {
 func (p *Preparer) Finish() {
 	p.Writer.Finish()
 
+	if p.statePath != "" {
+		p.history.prune(time.Now())
+
+		p.history.mu.Lock()
+		err := state.Save(p.statePath, p.history)
+		p.history.mu.Unlock()
+
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "path": p.statePath}).Error("error save vm state")
+		}
+	}
+
 	log.WithFields(log.Fields{"type": "server"}).Debug("finished")
 }
 
-func getSiteName() string {
-	siteName := viper.GetString(constants.CfgSiteName)
+// getBenchmark returns benchmark of the rule matching the location of the server or benchmark mapped
+// by the flavor and host of the server.
+func (p *Preparer) getBenchmark(server *SFStruct, r *rule) (benchmark.Benchmark, bool) {
+	if b, ok := r.benchmark(); ok {
+		return b, true
+	}
+
+	var flavor string
+	if server.Flavor != nil {
+		flavor = server.Flavor.Name
+	}
+
+	return p.benchmark.Map(server.Location.Host, flavor, server.ExtraSpecs)
+}
+
+// lastResize returns start time of the last resize of the server or zero time, if it is not known.
+func lastResize(cr *reader.Reader, serverID string) time.Time {
+	var last time.Time
+
+	r, err := cr.ListInstanceActions(serverID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "id": serverID}).Error("error list instance actions")
+		return last
+	}
+
+	pages, err := r.AllPages()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "id": serverID}).Error("error get instance action pages")
+		return last
+	}
+
+	actions, err := instanceactions.ExtractInstanceActions(pages)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "id": serverID}).Error("error extract instance actions")
+		return last
+	}
+
+	for _, action := range actions {
+		if action.Action == resizeAction && action.StartTime.After(last) {
+			last = action.StartTime
+		}
+	}
+
+	return last
+}
+
+// getFqan returns FQAN of the user of the server mapped to a virtual organization or the FQAN of the project
+// when the mapping is not enabled.
+func (p *Preparer) getFqan(server *SFStruct) *wrappers.StringValue {
+	if p.vo.Enabled() {
+		return util.WrapStr(p.vo.Map(server.Project, server.Server.UserID).Fqan)
+	}
+
+	if server.Server.TenantID != "" {
+		return &wrappers.StringValue{Value: vo.LegacyFqan(server.Server.TenantID)}
+	}
+
+	return nil
+}
+
+func getSiteName(r *rule) string {
+	if r != nil && r.SiteName != "" {
+		return r.SiteName
+	}
+
+	siteName := config.SiteName(config.VM)
 	if siteName == "" {
 		log.WithFields(log.Fields{}).Error("no site name in configuration") // should never happen
 	}
@@ -166,21 +301,29 @@ func getSiteName() string {
 	return siteName
 }
 
-func getCloudComputeService() *wrappers.StringValue {
-	return util.WrapStr(viper.GetString(constants.CfgCloudComputeService))
-}
+// getCloudComputeService returns cloud compute service of the rule, of bare metal servers or of virtual
+// machines. The location of the server is added by the cloud compute service template, if any.
+func (p *Preparer) getCloudComputeService(r *rule, server *SFStruct) *wrappers.StringValue {
+	ccs := config.CloudComputeService(config.VM)
 
-func getGlobalUserName(p *Preparer, server *servers.Server) *wrappers.StringValue {
-	if p.userIdentity != nil {
-		return util.WrapStr(p.userIdentity[server.UserID])
+	if bm := viper.GetString(constants.CfgVMBareMetalCloudComputeService); server.Node != nil && bm != "" {
+		ccs = bm
 	}
 
-	return nil
+	if r != nil && r.CloudComputeService != "" {
+		ccs = r.CloudComputeService
+	}
+
+	if p.service != nil {
+		ccs = renderService(p.service, ccs, server.Location)
+	}
+
+	return util.WrapStr(ccs)
 }
 
-func getFqan(tenantID string) *wrappers.StringValue {
-	if tenantID != "" {
-		return &wrappers.StringValue{Value: "/" + tenantID + "/Role=NULL/Capability=NULL"}
+func getGlobalUserName(p *Preparer, server *servers.Server) *wrappers.StringValue {
+	if p.userIdentity != nil {
+		return util.WrapStr(p.userIdentity[server.UserID])
 	}
 
 	return nil
@@ -202,14 +345,6 @@ func getWallDuration(sTime, eTime *timestamp.Timestamp) *duration.Duration {
 	return nil
 } // todo should be /servers/{server_id}/diagnostics -> uptime
 
-func getCPUDuration(wallDuration *duration.Duration, cpuCount uint32) *duration.Duration {
-	if wallDuration != nil {
-		return &duration.Duration{Seconds: wallDuration.Seconds * int64(cpuCount)}
-	}
-
-	return nil
-}
-
 func getPublicIPCount(server *servers.Server) *wrappers.UInt64Value {
 	var sum int
 
@@ -232,6 +367,44 @@ func getPublicIPCount(server *servers.Server) *wrappers.UInt64Value {
 	return nil
 }
 
+// getDiskSize returns size (GB) of root, ephemeral and swap disks of the flavor and attached volumes.
+// The root disk of the flavor is not used by servers booted from volume, the volume is counted instead.
+func getDiskSize(server *SFStruct) uint64 {
+	var size int
+
+	if server.Node != nil && server.Node.Disk > 0 {
+		size += server.Node.Disk
+	} else if server.Flavor != nil {
+		if getImageID(server.Server) != nil {
+			size += server.Flavor.Disk
+		}
+
+		size += server.Flavor.Ephemeral
+		size += (server.Flavor.Swap + 1023) / 1024 // translate MB to GB, rounded up
+	}
+
+	for _, volume := range server.Volumes {
+		size += volume
+	}
+
+	return uint64(size)
+}
+
+// getStorageRecordID returns IDs of volumes attached to the server separated by comma or nil when no volume
+// is attached. The volume storage records are identified by the volume ID.
+func getStorageRecordID(server *SFStruct) *wrappers.StringValue {
+	if len(server.Server.AttachedVolumes) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(server.Server.AttachedVolumes))
+	for _, volume := range server.Server.AttachedVolumes {
+		ids = append(ids, volume.ID)
+	}
+
+	return util.WrapStr(strings.Join(ids, ","))
+}
+
 func getImageID(server *servers.Server) *wrappers.StringValue {
 	id := server.Image["id"]
 	if id != nil {
@@ -241,11 +414,21 @@ func getImageID(server *servers.Server) *wrappers.StringValue {
 	return nil
 }
 
-func getCloudType() *wrappers.StringValue {
-	ct := viper.GetString(constants.CfgCloudType)
+// getCloudType returns cloud type of virtual machines or of bare metal servers, which defaults to the cloud
+// type of virtual machines with the bare metal suffix.
+func getCloudType(server *SFStruct) *wrappers.StringValue {
+	ct := config.CloudType(config.VM)
 	if ct == "" {
 		log.WithFields(log.Fields{}).Error(constants.ErrNoCloudType) // should never happen
 	}
 
+	if server.Node != nil {
+		if bm := viper.GetString(constants.CfgVMBareMetalCloudType); bm != "" {
+			return &wrappers.StringValue{Value: bm}
+		}
+
+		return &wrappers.StringValue{Value: ct + bareMetalCloudTypeSuffix}
+	}
+
 	return &wrappers.StringValue{Value: ct}
 }
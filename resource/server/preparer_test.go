@@ -0,0 +1,156 @@
+package server
+
+import (
+	"github.com/goat-project/goat-os/constants"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"github.com/spf13/viper"
+)
+
+var _ = ginkgo.Describe("Server Preparer tests", func() {
+	var (
+		server *SFStruct
+		flavor *flavors.Flavor
+	)
+
+	ginkgo.BeforeEach(func() {
+		flavor = &flavors.Flavor{Disk: 20, Ephemeral: 10, Swap: 512}
+	})
+
+	ginkgo.Describe("disk size", func() {
+		ginkgo.Context("when the server is booted from image", func() {
+			ginkgo.BeforeEach(func() {
+				server = &SFStruct{
+					Server: &servers.Server{Image: map[string]interface{}{"id": "image-id"},
+						AttachedVolumes: []servers.AttachedVolume{{ID: "volume-id"}}},
+					Flavor:  flavor,
+					Volumes: map[string]int{"volume-id": 100},
+				}
+			})
+
+			ginkgo.It("should count root, ephemeral and swap disks and attached volumes", func() {
+				gomega.Expect(getDiskSize(server)).To(gomega.Equal(uint64(20 + 10 + 1 + 100)))
+			})
+
+			ginkgo.It("should link the attached volumes", func() {
+				gomega.Expect(getStorageRecordID(server).Value).To(gomega.Equal("volume-id"))
+			})
+		})
+
+		ginkgo.Context("when the server is booted from volume", func() {
+			ginkgo.BeforeEach(func() {
+				server = &SFStruct{
+					Server:  &servers.Server{AttachedVolumes: []servers.AttachedVolume{{ID: "root"}, {ID: "data"}}},
+					Flavor:  flavor,
+					Volumes: map[string]int{"root": 50, "data": 5},
+				}
+			})
+
+			ginkgo.It("should count the root volume instead of root disk of the flavor", func() {
+				gomega.Expect(getDiskSize(server)).To(gomega.Equal(uint64(10 + 1 + 50 + 5)))
+			})
+
+			ginkgo.It("should link all attached volumes", func() {
+				gomega.Expect(getStorageRecordID(server).Value).To(gomega.Equal("root,data"))
+			})
+		})
+
+		ginkgo.Context("when the server runs on a bare metal node", func() {
+			ginkgo.It("should count local disk of the node instead of the flavor", func() {
+				server = &SFStruct{Server: &servers.Server{Image: map[string]interface{}{"id": "image-id"}},
+					Flavor: flavor, Node: &Node{Disk: 500}}
+
+				gomega.Expect(getDiskSize(server)).To(gomega.Equal(uint64(500)))
+			})
+
+			ginkgo.It("should count disks of the flavor when the node has no local disk", func() {
+				server = &SFStruct{Server: &servers.Server{Image: map[string]interface{}{"id": "image-id"}},
+					Flavor: flavor, Node: &Node{CPUs: 64}}
+
+				gomega.Expect(getDiskSize(server)).To(gomega.Equal(uint64(20 + 10 + 1)))
+			})
+		})
+
+		ginkgo.Context("when the server has no flavor and volumes", func() {
+			ginkgo.It("should return zero and no storage records", func() {
+				server = &SFStruct{Server: &servers.Server{}}
+
+				gomega.Expect(getDiskSize(server)).To(gomega.BeZero())
+				gomega.Expect(getStorageRecordID(server)).To(gomega.BeNil())
+			})
+		})
+	})
+
+	ginkgo.Describe("cloud type", func() {
+		ginkgo.BeforeEach(func() {
+			viper.Set(constants.CfgCloudType, "openstack")
+		})
+
+		ginkgo.AfterEach(func() {
+			viper.Reset()
+		})
+
+		ginkgo.It("should return cloud type of virtual machines", func() {
+			gomega.Expect(getCloudType(&SFStruct{}).Value).To(gomega.Equal("openstack"))
+		})
+
+		ginkgo.It("should return distinct cloud type of bare metal servers by default", func() {
+			gomega.Expect(getCloudType(&SFStruct{Node: &Node{}}).Value).To(gomega.Equal("openstack-bare-metal"))
+		})
+
+		ginkgo.It("should return configured cloud type of bare metal servers", func() {
+			viper.Set(constants.CfgVMBareMetalCloudType, "ironic")
+
+			gomega.Expect(getCloudType(&SFStruct{Node: &Node{}}).Value).To(gomega.Equal("ironic"))
+		})
+	})
+
+	ginkgo.Describe("cloud compute service", func() {
+		var preparer *Preparer
+
+		ginkgo.BeforeEach(func() {
+			viper.Set(constants.CfgCloudComputeService, "compute")
+
+			server = &SFStruct{Server: &servers.Server{}, Location: Location{AvailabilityZone: "zone-a",
+				Host: "compute-1", Aggregates: []string{"gpu", "ssd"}, HypervisorType: "QEMU"}}
+		})
+
+		ginkgo.JustBeforeEach(func() {
+			preparer = &Preparer{service: readServiceTemplate()}
+		})
+
+		ginkgo.AfterEach(func() {
+			viper.Reset()
+		})
+
+		ginkgo.Context("when no template is configured", func() {
+			ginkgo.It("should not change the cloud compute service", func() {
+				gomega.Expect(preparer.getCloudComputeService(nil, server).Value).To(gomega.Equal("compute"))
+			})
+		})
+
+		ginkgo.Context("when the template is configured", func() {
+			ginkgo.BeforeEach(func() {
+				viper.Set(constants.CfgVMCloudComputeServiceTemplate,
+					`{{.Service}}/{{.AvailabilityZone}}/{{.Host}}/{{join .Aggregates ","}}/{{.HypervisorType}}`)
+			})
+
+			ginkgo.It("should write the location of the server into the cloud compute service", func() {
+				gomega.Expect(preparer.getCloudComputeService(nil, server).Value).To(gomega.Equal(
+					"compute/zone-a/compute-1/gpu,ssd/QEMU"))
+			})
+
+			ginkgo.It("should render the cloud compute service of the matching rule", func() {
+				r := &rule{AvailabilityZone: "zone-*", CloudComputeService: "gpu"}
+
+				gomega.Expect(preparer.getCloudComputeService(r, server).Value).To(gomega.Equal(
+					"gpu/zone-a/compute-1/gpu,ssd/QEMU"))
+			})
+		})
+	})
+})
@@ -10,16 +10,30 @@ import (
 	"github.com/goat-project/goat-os/resource"
 
 	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/amphorae"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 )
 
 // Processor to process server's data.
 type Processor struct {
 	reader reader.Reader
+
+	aggregatesOnce  sync.Once
+	aggregates      map[string][]string
+	hypervisorsOnce sync.Once
+	hypervisors     map[string]string
+	nodesOnce       sync.Once
+	nodes           map[string]*Node
+	amphoraeOnce    sync.Once
+	amphorae        map[string]bool
+	historyOnce     sync.Once
+	history         *history
 }
 
 // CreateProcessor creates processor with reader.
@@ -35,7 +49,7 @@ func CreateProcessor(r *reader.Reader) *Processor {
 }
 
 func (p *Processor) createReader(osClient *gophercloud.ProviderClient) {
-	cClient, err := auth.CreateComputeV2ServiceClient(osClient)
+	cClient, err := auth.CreateComputeV2EmbeddedFlavorServiceClient(osClient)
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Error("unable to create Compute V2 service client")
 		return
@@ -74,7 +88,13 @@ func (p *Processor) Process(project projects.Project, osClient *gophercloud.Prov
 		return
 	}
 
+	usageCheck := viper.GetBool(constants.CfgVMUsageCheck)
+
 	if len(s) < 1 {
+		if usageCheck {
+			p.checkUsage(project, osClient, nil) // deleted servers are only in the tenant usage
+		}
+
 		return
 	}
 
@@ -84,23 +104,196 @@ func (p *Processor) Process(project projects.Project, osClient *gophercloud.Prov
 		log.WithFields(log.Fields{"error": err}).Error("error list flavors")
 	}
 
-	if flavorsMap == nil {
-		for i := range s {
-			read <- &SFStruct{Server: &s[i], Flavor: nil}
+	volumeSizes := listVolumeSizes(osClient, project.ID, s)
+
+	locs := p.locate(pages)
+
+	bareMetal := viper.GetBool(constants.CfgVMBareMetal)
+
+	var accounted []*SFStruct
+
+	for i := range s {
+		if viper.GetBool(constants.CfgVMExcludeAmphorae) && p.amphoraServers(osClient)[s[i].ID] {
+			continue // amphora servers are accounted with their load balancers
 		}
 
-		return
+		sf := &SFStruct{Project: &project, Server: &s[i], Flavor: serverFlavor(&s[i], flavorsMap),
+			Volumes: attachedVolumes(&s[i], volumeSizes), Location: locs[s[i].ID], ExtraSpecs: extraSpecs(&s[i])}
+
+		if p.flavorHistory().resizeNeeded(s[i].ID, sf.Flavor) {
+			sf.Resized = lastResize(&p.reader, s[i].ID)
+		}
+
+		if bareMetal && isBareMetal(sf.Location, sf.ExtraSpecs) {
+			sf.Node = p.bareMetalNodes(osClient)[s[i].ID]
+			if sf.Node == nil {
+				log.WithFields(log.Fields{"id": s[i].ID}).Error("error find bare metal node of the server")
+			}
+		}
+
+		if usageCheck {
+			accounted = append(accounted, sf)
+		}
+
+		read <- sf
 	}
 
-	for i := range s {
-		var flavor *flavors.Flavor
+	if usageCheck {
+		p.checkUsage(project, osClient, accounted)
+	}
+}
+
+// serverFlavor returns flavor embedded in the server (compute API 2.47+), which is available also for deleted
+// and private flavors. Otherwise, it returns the flavor with the ID of the server's flavor, if any.
+func serverFlavor(server *servers.Server, flavorsMap map[string]*flavors.Flavor) *flavors.Flavor {
+	if flavor := EmbeddedFlavor(server); flavor != nil {
+		return flavor
+	}
+
+	if fid, ok := server.Flavor["id"].(string); ok {
+		return flavorsMap[fid]
+	}
+
+	return nil
+}
+
+// EmbeddedFlavor returns flavor embedded in the server (compute API 2.47+) or nil.
+func EmbeddedFlavor(server *servers.Server) *flavors.Flavor {
+	if _, ok := server.Flavor["vcpus"]; !ok {
+		return nil
+	}
+
+	flavor := &flavors.Flavor{
+		VCPUs:     intValue(server.Flavor["vcpus"]),
+		RAM:       intValue(server.Flavor["ram"]),
+		Disk:      intValue(server.Flavor["disk"]),
+		Ephemeral: intValue(server.Flavor["ephemeral"]),
+		Swap:      intValue(server.Flavor["swap"]),
+	}
+	flavor.Name, _ = server.Flavor["original_name"].(string)
+
+	return flavor
+}
+
+// extraSpecs returns extra specs of the flavor embedded in the server (compute API 2.47+).
+func extraSpecs(server *servers.Server) map[string]string {
+	specs, ok := server.Flavor["extra_specs"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	extraSpecs := make(map[string]string)
+	for key, value := range specs {
+		if v, ok := value.(string); ok {
+			extraSpecs[key] = v
+		}
+	}
+
+	return extraSpecs
+}
+
+func intValue(value interface{}) int {
+	if v, ok := value.(float64); ok {
+		return int(v)
+	}
 
-		fid := s[i].Flavor["id"]
-		if fid != nil {
-			flavor = flavorsMap[fid.(string)]
+	return 0
+}
+
+// amphoraServers returns IDs of servers of load balancer amphorae, the amphorae are read only once.
+func (p *Processor) amphoraServers(osClient *gophercloud.ProviderClient) map[string]bool {
+	p.amphoraeOnce.Do(func() {
+		p.amphorae = make(map[string]bool)
+
+		client, err := auth.CreateLoadBalancerV2ServiceClient(osClient)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("unable to create Load Balancer V2 service client")
+			return
+		}
+
+		r, err := reader.CreateReader(client).ListAmphorae()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error list amphorae")
+			return
+		}
+
+		pages, err := r.AllPages()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error get amphora pages")
+			return
+		}
+
+		amps, err := amphorae.ExtractAmphorae(pages)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("error extract amphorae")
+			return
+		}
+
+		for _, amphora := range amps {
+			p.amphorae[amphora.ComputeID] = true
 		}
-		read <- &SFStruct{Server: &s[i], Flavor: flavor}
+	})
+
+	return p.amphorae
+}
+
+// listVolumeSizes returns sizes (GB) of volumes of the project by volume ID. Volumes are listed only when
+// a server has an attached volume.
+func listVolumeSizes(osClient *gophercloud.ProviderClient, projectID string,
+	servs []servers.Server) map[string]int {
+	attached := false
+	for i := range servs {
+		attached = attached || len(servs[i].AttachedVolumes) > 0
+	}
+
+	if !attached {
+		return nil
 	}
+
+	client, err := auth.CreateNewBlockStorageV3ServiceClient(osClient)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("unable to create New Block Storage V3 service client")
+		return nil
+	}
+
+	vols, err := reader.CreateReader(client).ListAllVolumes(projectID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error list volumes")
+		return nil
+	}
+
+	pages, err := vols.AllPages()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error get volume pages")
+		return nil
+	}
+
+	v, err := volumes.ExtractVolumes(pages)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error extract volumes")
+		return nil
+	}
+
+	sizes := make(map[string]int)
+	for _, volume := range v {
+		sizes[volume.ID] = volume.Size
+	}
+
+	return sizes
+}
+
+// attachedVolumes returns sizes of volumes attached to the server.
+func attachedVolumes(server *servers.Server, sizes map[string]int) map[string]int {
+	if len(server.AttachedVolumes) == 0 {
+		return nil
+	}
+
+	attached := make(map[string]int)
+	for _, volume := range server.AttachedVolumes {
+		attached[volume.ID] = sizes[volume.ID]
+	}
+
+	return attached
 }
 
 func (p *Processor) listAllFlavors(osClient *gophercloud.ProviderClient) (map[string]*flavors.Flavor, error) {
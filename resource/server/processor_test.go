@@ -0,0 +1,29 @@
+package server
+
+import (
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Server Processor tests", func() {
+	flavor := &flavors.Flavor{Name: "small", VCPUs: 1}
+
+	ginkgo.Describe("server flavor", func() {
+		ginkgo.It("should use flavor embedded in the server", func() {
+			s := &servers.Server{Flavor: map[string]interface{}{"original_name": "deleted", "vcpus": float64(2),
+				"ram": float64(2048), "disk": float64(20), "ephemeral": float64(0), "swap": float64(0)}}
+
+			gomega.Expect(serverFlavor(s, nil)).To(gomega.Equal(&flavors.Flavor{Name: "deleted", VCPUs: 2, RAM: 2048,
+				Disk: 20}))
+		})
+
+		ginkgo.It("should use flavor with the ID of the server's flavor", func() {
+			s := &servers.Server{Flavor: map[string]interface{}{"id": "flavor-id"}}
+
+			gomega.Expect(serverFlavor(s, map[string]*flavors.Flavor{"flavor-id": flavor})).To(gomega.Equal(flavor))
+		})
+	})
+})
@@ -1,9 +1,15 @@
 package reader
 
 import (
+	"time"
+
+	"github.com/goat-project/goat-os/result"
+
 	"github.com/gophercloud/gophercloud/pagination"
 
 	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/instanceactions"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/usage"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 )
 
@@ -16,3 +22,36 @@ type Servers struct {
 func (s *Servers) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
 	return servers.List(client, servers.ListOpts{TenantID: s.ProjectID})
 }
+
+// Server structure for a Reader which reads a server.
+type Server struct {
+	ID string
+}
+
+// ReadResource reads the server.
+func (s *Server) ReadResource(client *gophercloud.ServiceClient) result.Result {
+	return servers.Get(client, s.ID)
+}
+
+// InstanceActions structure for a Reader which reads an array of actions of a server.
+type InstanceActions struct {
+	ServerID string
+}
+
+// ReadResources reads actions of the server.
+func (ia *InstanceActions) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return instanceactions.List(client, ia.ServerID, nil)
+}
+
+// TenantUsage structure for a Reader which reads usage of servers of a project computed by Nova
+// (os-simple-tenant-usage) for a period.
+type TenantUsage struct {
+	ProjectID string
+	Start     time.Time
+	End       time.Time
+}
+
+// ReadResources reads usage of the project, it includes also deleted servers.
+func (tu *TenantUsage) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return usage.SingleTenant(client, tu.ProjectID, usage.SingleTenantOpts{Start: &tu.Start, End: &tu.End})
+}
@@ -1,14 +1,29 @@
 package server
 
 import (
+	"time"
+
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
 )
 
-// SFStruct represents "Resource" with information about server and his flavor.
+// SFStruct represents "Resource" with information about server, his project and flavor.
 type SFStruct struct {
-	Server *servers.Server
-	Flavor *flavors.Flavor
+	Project *projects.Project
+	Server  *servers.Server
+	Flavor  *flavors.Flavor
+	// Volumes contains sizes (GB) of volumes attached to the server by volume ID
+	Volumes map[string]int
+	// Location is where the server runs and ExtraSpecs are extra specs of its flavor, they are used
+	// to map the server to a benchmark, site name and cloud compute service
+	Location   Location
+	ExtraSpecs map[string]string
+	// Node is the bare metal node the server runs on, if any
+	Node *Node
+	// Resized is time of the last resize of the server, it is resolved only when the flavor differs from
+	// the history of flavors, zero otherwise or when it is not known
+	Resized time.Time
 }
 
 // UnmarshalJSON function to implement Resource interface.
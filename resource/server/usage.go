@@ -0,0 +1,220 @@
+package server
+
+import (
+	"math"
+	"time"
+
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/filter"
+	"github.com/goat-project/goat-os/state"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/usage"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/pagination"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// default relative difference (percent) of usages which is not reported
+const defaultUsageTolerance = 1.0
+
+// usageTotals are server hours, vCPU hours and RAM MB-hours of servers of a project in a period.
+type usageTotals struct {
+	Hours         float64
+	VCPUHours     float64
+	MemoryMBHours float64
+}
+
+func (u *usageTotals) add(hours float64, vcpus, memory int) {
+	u.Hours += hours
+	u.VCPUHours += hours * float64(vcpus)
+	u.MemoryMBHours += hours * float64(memory)
+}
+
+// differs returns whether any of the usages differs from the other one by more than the tolerance (percent).
+func (u usageTotals) differs(other usageTotals, tolerance float64) bool {
+	return differ(u.Hours, other.Hours, tolerance) ||
+		differ(u.VCPUHours, other.VCPUHours, tolerance) ||
+		differ(u.MemoryMBHours, other.MemoryMBHours, tolerance)
+}
+
+func differ(a, b, tolerance float64) bool {
+	return math.Abs(a-b) > math.Max(a, b)*tolerance/100
+}
+
+// ownUsage returns usage of the servers in the period computed the same way as their records, with sizes
+// of their flavors from the history of flavors or of bare metal nodes. The listed servers are not deleted,
+// they run to the end of the period.
+func ownUsage(servers []*SFStruct, h *history, from, to time.Time) usageTotals {
+	var u usageTotals
+
+	for _, server := range servers {
+		start := server.Server.Created
+		if start.Before(from) {
+			start = from
+		}
+
+		if !to.After(start) {
+			continue
+		}
+
+		u.Hours += to.Sub(start).Hours()
+
+		intervals := h.flavors(server.Server.ID, server.Flavor, server.Server.Created, to, server.Resized)
+		if len(intervals) == 0 {
+			intervals = []flavorInterval{{Since: server.Server.Created}}
+		}
+
+		for i, interval := range intervals {
+			s := interval.Since
+			if s.Before(start) {
+				s = start
+			}
+
+			e := to
+			if i+1 < len(intervals) && intervals[i+1].Since.Before(e) {
+				e = intervals[i+1].Since
+			}
+
+			if !e.After(s) {
+				continue
+			}
+
+			vcpus, memory := nodeSize(server.Node, interval.VCPUs, interval.RAM)
+			u.VCPUHours += e.Sub(s).Hours() * float64(vcpus)
+			u.MemoryMBHours += e.Sub(s).Hours() * float64(memory)
+		}
+	}
+
+	return u
+}
+
+// nodeSize returns CPUs and memory of the bare metal node, if known, or the given CPUs and memory of the flavor.
+func nodeSize(node *Node, vcpus, memory int) (int, int) {
+	if node != nil && node.CPUs > 0 {
+		vcpus = node.CPUs
+	}
+
+	if node != nil && node.Memory > 0 {
+		memory = node.Memory
+	}
+
+	return vcpus, memory
+}
+
+// tenantUsage returns usage of servers of the project computed by Nova.
+func tenantUsage(pager pagination.Pager) ([]usage.ServerUsage, error) {
+	var servers []usage.ServerUsage
+
+	err := pager.EachPage(func(page pagination.Page) (bool, error) {
+		tu, err := usage.ExtractSingleTenant(page)
+		if err != nil {
+			return false, err
+		}
+
+		servers = append(servers, tu.ServerUsages...)
+
+		return true, nil
+	})
+
+	return servers, err
+}
+
+// flavorHistory returns history of server flavors stored by previous runs, the history is read only once and
+// it is not changed by the processor.
+func (p *Processor) flavorHistory() *history {
+	p.historyOnce.Do(func() {
+		p.history = createHistory()
+
+		path := viper.GetString(constants.CfgVMStatePath)
+		if path == "" {
+			return
+		}
+
+		if err := state.Load(path, p.history); err != nil {
+			log.WithFields(log.Fields{"error": err, "path": path}).Error("error load vm state")
+		}
+
+		if p.history.Servers == nil {
+			p.history.Servers = make(map[string]*serverHistory)
+		}
+	})
+
+	return p.history
+}
+
+// checkUsage compares usage of the accounted servers of the project in the period of records with usage
+// computed by Nova (os-simple-tenant-usage) and logs the differences. Nova includes also deleted servers,
+// which are not listed, they are logged as missing. Excluded amphorae are left out of both usages.
+func (p *Processor) checkUsage(project projects.Project, osClient *gophercloud.ProviderClient, servers []*SFStruct) {
+	from, to := filter.Period()
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+
+	pager, err := p.reader.ListTenantUsage(project.ID, from, to)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": project.ID}).Error("error list tenant usage")
+		return
+	}
+
+	novaServers, err := tenantUsage(pager)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": project.ID}).Error("error extract tenant usage")
+		return
+	}
+
+	accounted := make(map[string]bool, len(servers))
+	for _, server := range servers {
+		accounted[server.Server.ID] = true
+	}
+
+	excludeAmphorae := viper.GetBool(constants.CfgVMExcludeAmphorae)
+
+	var nova, missing usageTotals
+	var missingCount int
+
+	for _, server := range novaServers {
+		if excludeAmphorae && p.amphoraServers(osClient)[server.InstanceID] {
+			continue
+		}
+
+		nova.add(server.Hours, server.VCPUs, server.MemoryMB)
+
+		if accounted[server.InstanceID] {
+			continue
+		}
+
+		missingCount++
+		missing.add(server.Hours, server.VCPUs, server.MemoryMB)
+
+		log.WithFields(log.Fields{"project": project.ID, "id": server.InstanceID, "name": server.Name,
+			"state": server.State, "hours": server.Hours}).Debug("server in tenant usage is not accounted")
+	}
+
+	tolerance := defaultUsageTolerance
+	if viper.IsSet(constants.CfgVMUsageCheckTolerance) {
+		tolerance = viper.GetFloat64(constants.CfgVMUsageCheckTolerance)
+	}
+
+	own := ownUsage(servers, p.flavorHistory(), from, to)
+	if !own.differs(nova, tolerance) {
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"project":                 project.ID,
+		"hours":                   own.Hours,
+		"nova-hours":              nova.Hours,
+		"vcpu-hours":              own.VCPUHours,
+		"nova-vcpu-hours":         nova.VCPUHours,
+		"memory-mb-hours":         own.MemoryMBHours,
+		"nova-memory-mb-hours":    nova.MemoryMBHours,
+		"missing-servers":         missingCount,
+		"missing-hours":           missing.Hours,
+		"missing-vcpu-hours":      missing.VCPUHours,
+		"missing-memory-mb-hours": missing.MemoryMBHours,
+	}).Warn("usage of servers differs from tenant usage")
+}
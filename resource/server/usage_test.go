@@ -0,0 +1,72 @@
+package server
+
+import (
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Server Usage tests", func() {
+	to := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	from := to.Add(-10 * time.Hour)
+
+	ginkgo.Describe("own usage", func() {
+		ginkgo.It("should count hours of the servers in the period", func() {
+			u := ownUsage([]*SFStruct{
+				{Server: &servers.Server{ID: "a", Created: from.Add(-time.Hour)},
+					Flavor: &flavors.Flavor{VCPUs: 2, RAM: 1024}},
+				{Server: &servers.Server{ID: "b", Created: to.Add(-time.Hour)}, Node: &Node{CPUs: 64, Memory: 2048}},
+				{Server: &servers.Server{ID: "c", Created: to.Add(time.Hour)},
+					Flavor: &flavors.Flavor{VCPUs: 2, RAM: 1024}},
+			}, createHistory(), from, to)
+
+			gomega.Expect(u).To(gomega.Equal(usageTotals{Hours: 11, VCPUHours: 84, MemoryMBHours: 12288}))
+		})
+
+		ginkgo.It("should count hours of servers without flavor", func() {
+			u := ownUsage([]*SFStruct{{Server: &servers.Server{ID: "a", Created: from}}}, createHistory(), from, to)
+
+			gomega.Expect(u).To(gomega.Equal(usageTotals{Hours: 10}))
+		})
+
+		ginkgo.It("should take size of the flavor when the bare metal node does not have it", func() {
+			u := ownUsage([]*SFStruct{{Server: &servers.Server{ID: "a", Created: from},
+				Flavor: &flavors.Flavor{VCPUs: 2, RAM: 1024}, Node: &Node{CPUs: 64}}}, createHistory(), from, to)
+
+			gomega.Expect(u).To(gomega.Equal(usageTotals{Hours: 10, VCPUHours: 640, MemoryMBHours: 10240}))
+		})
+
+		ginkgo.It("should count resized servers with their previous flavors", func() {
+			h := createHistory()
+			h.observe("a", &flavors.Flavor{Name: "small", VCPUs: 1, RAM: 1024}, from, from, time.Time{})
+
+			u := ownUsage([]*SFStruct{{Server: &servers.Server{ID: "a", Created: from},
+				Flavor: &flavors.Flavor{Name: "large", VCPUs: 4, RAM: 4096}, Resized: from.Add(4 * time.Hour)}},
+				h, from, to)
+
+			gomega.Expect(u).To(gomega.Equal(usageTotals{Hours: 10, VCPUHours: 4 + 24,
+				MemoryMBHours: 4*1024 + 6*4096}))
+			gomega.Expect(h.Servers["a"].Flavors).To(gomega.HaveLen(1))
+		})
+	})
+
+	ginkgo.Describe("differs", func() {
+		u := usageTotals{Hours: 100, VCPUHours: 200, MemoryMBHours: 400}
+
+		ginkgo.It("should not report differences within the tolerance", func() {
+			gomega.Expect(u.differs(usageTotals{Hours: 100.5, VCPUHours: 199, MemoryMBHours: 400}, 1)).To(
+				gomega.BeFalse())
+		})
+
+		ginkgo.It("should report differences over the tolerance", func() {
+			gomega.Expect(u.differs(usageTotals{Hours: 100, VCPUHours: 210, MemoryMBHours: 400}, 1)).To(
+				gomega.BeTrue())
+			gomega.Expect(u.differs(usageTotals{Hours: 100.5, VCPUHours: 200, MemoryMBHours: 400}, 0)).To(
+				gomega.BeTrue())
+		})
+	})
+})
@@ -17,7 +17,8 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// Writer structure to write virtual machine data to Goat server.
+// Writer structure to write virtual machine data to Goat server. Clusters and load balancers are written
+// as virtual machine records too.
 type Writer struct {
 	Stream      pb.AccountingService_ProcessVmsClient
 	rateLimiter *rate.Limiter
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"github.com/gophercloud/gophercloud/pagination"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type slicePage interface {
+	ExtractIntoSlicePtr(to interface{}, label string) error
+}
+
+// extractAttribute returns map of resource IDs to the string attribute of the resources. It is used for
+// attributes which are not part of gophercloud structures.
+func extractAttribute(pages pagination.Page, label, attribute string) map[string]string {
+	var s []map[string]interface{}
+
+	attributes := make(map[string]string)
+
+	page, ok := pages.(slicePage)
+	if !ok {
+		log.WithFields(log.Fields{"type": pages}).Error("error unknown type of page")
+		return attributes
+	}
+
+	if err := page.ExtractIntoSlicePtr(&s, label); err != nil {
+		log.WithFields(log.Fields{"error": err, "attribute": attribute}).Error("error extract attribute")
+		return attributes
+	}
+
+	for _, r := range s {
+		id, _ := r["id"].(string)
+		value, _ := r[attribute].(string)
+		attributes[id] = value
+	}
+
+	return attributes
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+const volumesFixture = `{"volumes": [
+	{"id": "1", "user_id": "user-1"},
+	{"id": "2"}]}`
+
+func pageResult(fixture string) pagination.PageResult {
+	var body map[string]interface{}
+	gomega.Expect(json.Unmarshal([]byte(fixture), &body)).To(gomega.Succeed())
+
+	return pagination.PageResult{Result: gophercloud.Result{Body: body}}
+}
+
+var _ = ginkgo.Describe("Storage Attribute tests", func() {
+	ginkgo.Describe("extract attribute", func() {
+		ginkgo.It("should map resource IDs to the attribute", func() {
+			page := volumes.VolumePage{LinkedPageBase: pagination.LinkedPageBase{PageResult: pageResult(volumesFixture)}}
+
+			gomega.Expect(extractAttribute(page, "volumes", "user_id")).To(gomega.Equal(map[string]string{
+				"1": "user-1",
+				"2": "",
+			}))
+		})
+	})
+})
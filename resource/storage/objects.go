@@ -1,17 +1,27 @@
 package storage
 
 import (
+	"time"
+
+	storageReader "github.com/goat-project/goat-os/resource/storage/reader"
+
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/backups"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
 	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
 	"github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/shares"
+	manilaSnapshots "github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/snapshots"
 )
 
 // SwiftContainer represents "Resource" with information about project and his container.
 type SwiftContainer struct {
 	Project   *projects.Project
 	Container *containers.Container
+	// CreatedAt and StoragePolicy are read from headers of the container
+	CreatedAt     time.Time
+	StoragePolicy string
 }
 
 // UnmarshalJSON function to implement Resource interface.
@@ -19,6 +29,19 @@ func (sc *SwiftContainer) UnmarshalJSON(b []byte) error {
 	return sc.Project.UnmarshalJSON(b)
 }
 
+// SwiftAccount represents "Resource" with information about project and quota of his object storage account.
+type SwiftAccount struct {
+	Project *projects.Project
+	// Quota is the quota of the account and Used are bytes used by containers of the account
+	Quota int64
+	Used  int64
+}
+
+// UnmarshalJSON function to implement Resource interface.
+func (sa *SwiftAccount) UnmarshalJSON(b []byte) error {
+	return sa.Project.UnmarshalJSON(b)
+}
+
 // PVolume represents "Resource" with information about project and his volume.
 type PVolume struct {
 	Project *projects.Project
@@ -30,10 +53,38 @@ func (pv *PVolume) UnmarshalJSON(b []byte) error {
 	return pv.Project.UnmarshalJSON(b)
 }
 
+// PSnapshot represents "Resource" with information about project and his volume snapshot.
+type PSnapshot struct {
+	Project  *projects.Project
+	Snapshot *snapshots.Snapshot
+	// UserID is the ID of the user who created the snapshot, it is not part of snapshots.Snapshot
+	UserID string
+}
+
+// UnmarshalJSON function to implement Resource interface.
+func (ps *PSnapshot) UnmarshalJSON(b []byte) error {
+	return ps.Project.UnmarshalJSON(b)
+}
+
+// PBackup represents "Resource" with information about project and his volume backup.
+type PBackup struct {
+	Project *projects.Project
+	Backup  *backups.Backup
+	// UserID is the ID of the user who created the backup, it is not part of backups.Backup
+	UserID string
+}
+
+// UnmarshalJSON function to implement Resource interface.
+func (pb *PBackup) UnmarshalJSON(b []byte) error {
+	return pb.Project.UnmarshalJSON(b)
+}
+
 // PShare represents "Resource" with information about project and his share.
 type PShare struct {
 	Project *projects.Project
 	Share   *shares.Share
+	// UserID is the ID of the user who created the share, it is not part of shares.Share
+	UserID string
 }
 
 // UnmarshalJSON function to implement Resource interface.
@@ -41,6 +92,34 @@ func (ps *PShare) UnmarshalJSON(b []byte) error {
 	return ps.Project.UnmarshalJSON(b)
 }
 
+// PShareSnapshot represents "Resource" with information about project and his share snapshot.
+type PShareSnapshot struct {
+	Project  *projects.Project
+	Snapshot *manilaSnapshots.Snapshot
+	// UserID is the ID of the user who created the snapshot, it is not part of snapshots.Snapshot
+	UserID string
+}
+
+// UnmarshalJSON function to implement Resource interface.
+func (ps *PShareSnapshot) UnmarshalJSON(b []byte) error {
+	return ps.Project.UnmarshalJSON(b)
+}
+
+// PShareReplica represents "Resource" with information about project and his share replica.
+type PShareReplica struct {
+	Project *projects.Project
+	Replica *storageReader.Replica
+	// Share is the parent share of the replica
+	Share *shares.Share
+	// UserID is the ID of the user who created the parent share
+	UserID string
+}
+
+// UnmarshalJSON function to implement Resource interface.
+func (pr *PShareReplica) UnmarshalJSON(b []byte) error {
+	return pr.Project.UnmarshalJSON(b)
+}
+
 // PImage represents "Resource" with information about project and his image.
 type PImage struct {
 	Project *projects.Project
@@ -5,14 +5,19 @@ import (
 	"sync"
 	"time"
 
+	"github.com/goat-project/goat-os/config"
 	"github.com/goat-project/goat-os/constants"
 	"github.com/goat-project/goat-os/initialize"
 	"github.com/goat-project/goat-os/reader"
 	"github.com/goat-project/goat-os/resource"
 	"github.com/goat-project/goat-os/util"
+	"github.com/goat-project/goat-os/vo"
 	"github.com/goat-project/goat-os/writer"
 	pb "github.com/goat-project/goat-proto-go"
 
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/golang/protobuf/ptypes/wrappers"
 
@@ -26,11 +31,18 @@ import (
 	"github.com/beevik/guid"
 )
 
+// type of group attribute of storage records, the attribute is a role of the user in the group
+const roleAttributeType = "role"
+
+// property of image with ID of the user who created the image
+const imageUserProperty = "user_id"
+
 // Preparer to prepare storage data to specific structure for writing to Goat server.
 type Preparer struct {
 	reader       reader.Reader
 	Writer       writer.Writer
 	userIdentity map[string]string
+	vo           *vo.Mapper
 }
 
 // CreatePreparer creates Preparer for storage records.
@@ -53,6 +65,7 @@ func CreatePreparer(ir *reader.Reader, limiter *rate.Limiter, conn *grpc.ClientC
 	return &Preparer{
 		reader: *ir,
 		Writer: *writer.CreateWriter(CreateWriter(limiter), conn),
+		vo:     vo.CreateMapper(ir),
 	}
 }
 
@@ -72,16 +85,37 @@ func (p *Preparer) Preparation(acc resource.Resource, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	var storageRecord *pb.StorageRecord
+	var project *projects.Project
+	var user string
 
 	switch t := acc.(type) {
 	case *PImage:
 		storageRecord = prepareImage(t)
+		project, user = t.Project, imageOwner(t.Image)
 	case *PShare:
 		storageRecord = prepareShare(t)
+		project, user = t.Project, t.UserID
+	case *PShareSnapshot:
+		storageRecord = prepareShareSnapshot(t)
+		project, user = t.Project, t.UserID
+	case *PShareReplica:
+		storageRecord = prepareShareReplica(t)
+		project, user = t.Project, t.UserID
 	case *PVolume:
 		storageRecord = prepareVolume(t)
+		project, user = t.Project, t.Volume.UserID
+	case *PSnapshot:
+		storageRecord = prepareSnapshot(t)
+		project, user = t.Project, t.UserID
+	case *PBackup:
+		storageRecord = prepareBackup(t)
+		project, user = t.Project, t.UserID
 	case *SwiftContainer:
 		storageRecord = prepareSwiftContainer(t)
+		project = t.Project
+	case *SwiftAccount:
+		storageRecord = prepareSwiftAccount(t)
+		project = t.Project
 	default:
 		log.WithFields(log.Fields{"type": t}).Error("error unknown type")
 	}
@@ -91,11 +125,49 @@ func (p *Preparer) Preparation(acc resource.Resource, wg *sync.WaitGroup) {
 		return
 	}
 
+	storageRecord.LocalUser, storageRecord.UserIdentity = p.owner(project, user)
+	storageRecord.LocalGroup = util.WrapStr(project.ID)
+
+	storageRecord.Group = util.WrapStr(project.Name)
+
+	if p.vo.Enabled() {
+		group := p.vo.Map(project, user)
+		storageRecord.Group = util.WrapStr(group.VO)
+		storageRecord.GroupAttribute = util.WrapStr(group.Role)
+		storageRecord.GroupAttributeType = util.WrapStr(roleAttributeType)
+	}
+
 	if err := p.Writer.Write(storageRecord); err != nil {
 		log.WithFields(log.Fields{"error": err}).Error(constants.ErrPrepWrite)
 	}
 }
 
+// owner returns local user and user identity of the storage owned by the user. If the user is unknown or the name
+// of the user cannot be resolved, the project is used instead.
+func (p *Preparer) owner(project *projects.Project, user string) (*wrappers.StringValue, *wrappers.StringValue) {
+	localUser, userIdentity := project.ID, project.Name
+
+	if user != "" {
+		localUser = user
+
+		if name := p.userIdentity[user]; name != "" {
+			userIdentity = name
+		}
+	}
+
+	return util.WrapStr(localUser), util.WrapStr(userIdentity)
+}
+
+// imageOwner returns ID of the user who created the image. Glance stores only the owning project, the user is
+// available in properties of images created as snapshots of servers.
+func imageOwner(image *images.Image) string {
+	if user, ok := image.Properties[imageUserProperty].(string); ok {
+		return user
+	}
+
+	return ""
+}
+
 // SendIdentifier sends identifier to Goat server.
 func (p *Preparer) SendIdentifier() error {
 	return p.Writer.SendIdentifier()
@@ -112,32 +184,34 @@ func (p *Preparer) Finish() {
 func prepareImage(storage *PImage) *pb.StorageRecord {
 	startTime := util.WrapTime(&storage.Image.CreatedAt)
 	now := time.Now().Unix()
-	size := uint64(storage.Image.SizeBytes)
+	// stored size of the image and virtual size of the disk created from the image, the virtual size is unknown
+	// for some disk formats and it can be smaller than the stored size for small images
+	used := uint64(storage.Image.SizeBytes)
+	allocated := used
+	if storage.Image.VirtualSize > storage.Image.SizeBytes {
+		allocated = uint64(storage.Image.VirtualSize)
+	}
 
 	return &pb.StorageRecord{
 		RecordID:      guid.New().String(),
 		CreateTime:    &timestamp.Timestamp{Seconds: now},
 		StorageSystem: viper.GetString(constants.CfgOpenstackIdentityEndpoint),
-		Site:          util.WrapStr(viper.GetString(constants.CfgSite)),
+		Site:          util.WrapStr(config.SiteName(config.Storage)),
 		StorageShare:  util.WrapStr("image"),
 		StorageMedia:  &wrappers.StringValue{Value: "disk"},
 		// StorageClass: nil,
-		FileCount: util.WrapStr(storage.Image.File),
+		FileCount: util.WrapStr("1"),
 		// DirectoryPath: nil,
-		LocalUser:    util.WrapStr(storage.Image.Owner), // todo owner user id
-		LocalGroup:   util.WrapStr(storage.Project.ID),
-		UserIdentity: util.WrapStr(storage.Image.Owner), // todo - owner's name
-		Group:        util.WrapStr(storage.Project.Name),
-		// GroupAttribute: nil,
-		// GroupAttributeType: nil,
 		StartTime:                 startTime,
 		EndTime:                   &timestamp.Timestamp{Seconds: now},
-		ResourceCapacityUsed:      size,
-		LogicalCapacityUsed:       &wrappers.UInt64Value{Value: size}, // todo - count
-		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: size}, // todo - count
+		ResourceCapacityUsed:      used,
+		LogicalCapacityUsed:       &wrappers.UInt64Value{Value: used},
+		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: allocated},
 	}
 }
 
+// prepareShare returns record of the share with its size as both allocated and used capacity. Manila does not
+// report usage of a share, only of the whole backend pool, which cannot be attributed to single shares.
 func prepareShare(storage *PShare) *pb.StorageRecord {
 	startTime := util.WrapTime(&storage.Share.CreatedAt)
 	now := time.Now().Unix()
@@ -147,80 +221,183 @@ func prepareShare(storage *PShare) *pb.StorageRecord {
 		RecordID:      guid.New().String(),
 		CreateTime:    &timestamp.Timestamp{Seconds: now},
 		StorageSystem: viper.GetString(constants.CfgOpenstackIdentityEndpoint),
-		Site:          util.WrapStr(viper.GetString(constants.CfgSite)),
+		Site:          util.WrapStr(config.SiteName(config.Storage)),
 		StorageShare:  util.WrapStr("share"),
 		StorageMedia:  &wrappers.StringValue{Value: "disk"},
 		// StorageClass: nil,
 		FileCount: util.WrapStr("1"),
 		// DirectoryPath: nil,
-		LocalUser:    util.WrapStr(storage.Share.ProjectID), // todo owner user id
-		LocalGroup:   util.WrapStr(storage.Project.ID),
-		UserIdentity: util.WrapStr(storage.Share.ProjectID), // todo - owner's name
-		Group:        util.WrapStr(storage.Project.Name),
-		// GroupAttribute: nil,
-		// GroupAttributeType: nil,
 		StartTime:                 startTime,
 		EndTime:                   &timestamp.Timestamp{Seconds: now},
 		ResourceCapacityUsed:      size,
-		LogicalCapacityUsed:       &wrappers.UInt64Value{Value: size}, // todo - count
-		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: size}, // todo - count
+		LogicalCapacityUsed:       &wrappers.UInt64Value{Value: size},
+		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: size},
 	}
 }
 
+func prepareShareSnapshot(storage *PShareSnapshot) *pb.StorageRecord {
+	startTime := util.WrapTime(&storage.Snapshot.CreatedAt)
+	now := time.Now().Unix()
+	size := uint64(storage.Snapshot.Size * 1024 * 1024 * 1024) // translate GB to bytes
+
+	return &pb.StorageRecord{
+		RecordID:                  guid.New().String(),
+		CreateTime:                &timestamp.Timestamp{Seconds: now},
+		StorageSystem:             viper.GetString(constants.CfgOpenstackIdentityEndpoint),
+		Site:                      util.WrapStr(config.SiteName(config.Storage)),
+		StorageShare:              util.WrapStr("share-snapshot"),
+		StorageMedia:              &wrappers.StringValue{Value: "disk"},
+		StorageClass:              util.WrapStr(viper.GetString(constants.CfgShareSnapshotClass)),
+		FileCount:                 util.WrapStr("1"),
+		DirectoryPath:             util.WrapStr(storage.Snapshot.ShareID), // parent share
+		StartTime:                 startTime,
+		EndTime:                   &timestamp.Timestamp{Seconds: now},
+		ResourceCapacityUsed:      size,
+		LogicalCapacityUsed:       &wrappers.UInt64Value{Value: size},
+		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: size},
+	}
+}
+
+func prepareShareReplica(storage *PShareReplica) *pb.StorageRecord {
+	startTime := util.WrapTime(&storage.Replica.CreatedAt)
+	now := time.Now().Unix()
+	size := uint64(storage.Share.Size * 1024 * 1024 * 1024) // replica has the size of the share, translate GB to bytes
+
+	return &pb.StorageRecord{
+		RecordID:                  guid.New().String(),
+		CreateTime:                &timestamp.Timestamp{Seconds: now},
+		StorageSystem:             viper.GetString(constants.CfgOpenstackIdentityEndpoint),
+		Site:                      util.WrapStr(config.SiteName(config.Storage)),
+		StorageShare:              util.WrapStr("share-replica"),
+		StorageMedia:              &wrappers.StringValue{Value: "disk"},
+		StorageClass:              util.WrapStr(viper.GetString(constants.CfgShareReplicaClass)),
+		FileCount:                 util.WrapStr("1"),
+		DirectoryPath:             util.WrapStr(storage.Share.ID), // parent share
+		StartTime:                 startTime,
+		EndTime:                   &timestamp.Timestamp{Seconds: now},
+		ResourceCapacityUsed:      size,
+		LogicalCapacityUsed:       &wrappers.UInt64Value{Value: size},
+		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: size},
+	}
+}
+
+// prepareVolume returns record of the volume with its size as both allocated and used capacity. Cinder does not
+// report usage of a volume, only of the whole backend pool, which cannot be attributed to single volumes.
 func prepareVolume(storage *PVolume) *pb.StorageRecord {
 	startTime := util.WrapTime(&storage.Volume.CreatedAt)
 	now := time.Now().Unix()
 	size := uint64(storage.Volume.Size * 1024 * 1024 * 1024) // translate GB to bytes
 
 	return &pb.StorageRecord{
-		RecordID:      guid.New().String(),
+		RecordID:      storage.Volume.ID, // VmRecord.StorageRecordId refers to volumes by ID
 		CreateTime:    &timestamp.Timestamp{Seconds: now},
 		StorageSystem: viper.GetString(constants.CfgOpenstackIdentityEndpoint),
-		Site:          util.WrapStr(viper.GetString(constants.CfgSite)),
+		Site:          util.WrapStr(config.SiteName(config.Storage)),
 		StorageShare:  util.WrapStr("volume"),
 		StorageMedia:  &wrappers.StringValue{Value: "disk"},
 		// StorageClass: nil,
+		FileCount:                 util.WrapStr("1"),
+		DirectoryPath:             util.WrapStr(storage.Volume.ID),
+		StartTime:                 startTime,
+		EndTime:                   &timestamp.Timestamp{Seconds: now},
+		ResourceCapacityUsed:      size,
+		LogicalCapacityUsed:       &wrappers.UInt64Value{Value: size},
+		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: size},
+	}
+}
+
+func prepareSnapshot(storage *PSnapshot) *pb.StorageRecord {
+	startTime := util.WrapTime(&storage.Snapshot.CreatedAt)
+	now := time.Now().Unix()
+	size := uint64(storage.Snapshot.Size * 1024 * 1024 * 1024) // translate GB to bytes
+
+	return &pb.StorageRecord{
+		RecordID:      guid.New().String(),
+		CreateTime:    &timestamp.Timestamp{Seconds: now},
+		StorageSystem: viper.GetString(constants.CfgOpenstackIdentityEndpoint),
+		Site:          util.WrapStr(config.SiteName(config.Storage)),
+		StorageShare:  util.WrapStr("snapshot"),
+		StorageMedia:  &wrappers.StringValue{Value: "disk"},
+		// StorageClass: nil,
 		FileCount: util.WrapStr("1"),
 		// DirectoryPath: nil,
-		LocalUser:    util.WrapStr(storage.Volume.UserID), // todo owner user id
-		LocalGroup:   util.WrapStr(storage.Project.ID),
-		UserIdentity: util.WrapStr(storage.Volume.UserID), // todo - owner's name
-		Group:        util.WrapStr(storage.Project.Name),
-		// GroupAttribute: nil,
-		// GroupAttributeType: nil,
 		StartTime:                 startTime,
 		EndTime:                   &timestamp.Timestamp{Seconds: now},
 		ResourceCapacityUsed:      size,
-		LogicalCapacityUsed:       &wrappers.UInt64Value{Value: size}, // todo - count
-		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: size}, // todo - count
+		LogicalCapacityUsed:       &wrappers.UInt64Value{Value: size},
+		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: size},
 	}
 }
 
-func prepareSwiftContainer(storage *SwiftContainer) *pb.StorageRecord {
-	// startTime := util.WrapTime(nil) // todo
+func prepareBackup(storage *PBackup) *pb.StorageRecord {
+	startTime := util.WrapTime(&storage.Backup.CreatedAt)
 	now := time.Now().Unix()
-	size := uint64(storage.Container.Bytes)
+	size := uint64(storage.Backup.Size * 1024 * 1024 * 1024) // translate GB to bytes
 
 	return &pb.StorageRecord{
 		RecordID:      guid.New().String(),
 		CreateTime:    &timestamp.Timestamp{Seconds: now},
 		StorageSystem: viper.GetString(constants.CfgOpenstackIdentityEndpoint),
-		Site:          util.WrapStr(viper.GetString(constants.CfgSite)),
-		StorageShare:  util.WrapStr("swift"),
+		Site:          util.WrapStr(config.SiteName(config.Storage)),
+		StorageShare:  util.WrapStr("backup"),
 		StorageMedia:  &wrappers.StringValue{Value: "disk"},
 		// StorageClass: nil,
-		FileCount: &wrappers.StringValue{Value: strconv.FormatInt(storage.Container.Count, 10)},
+		FileCount: util.WrapStr(strconv.Itoa(storage.Backup.ObjectCount)),
 		// DirectoryPath: nil,
-		LocalUser:    util.WrapStr(storage.Project.ID),
-		LocalGroup:   util.WrapStr(storage.Project.DomainID),
-		UserIdentity: util.WrapStr(storage.Project.Name),
-		Group:        util.WrapStr(storage.Project.Name),
-		// GroupAttribute: nil,
-		// GroupAttributeType: nil,
-		StartTime:                 &timestamp.Timestamp{Seconds: now}, // todo //startTime,
+		StartTime:                 startTime,
 		EndTime:                   &timestamp.Timestamp{Seconds: now},
 		ResourceCapacityUsed:      size,
-		LogicalCapacityUsed:       &wrappers.UInt64Value{Value: size}, // todo - count
-		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: size}, // todo - count
+		LogicalCapacityUsed:       &wrappers.UInt64Value{Value: size},
+		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: size},
+	}
+}
+
+func prepareSwiftContainer(storage *SwiftContainer) *pb.StorageRecord {
+	now := time.Now().Unix()
+	startTime := &timestamp.Timestamp{Seconds: now}
+	if !storage.CreatedAt.IsZero() {
+		startTime = util.WrapTime(&storage.CreatedAt)
+	}
+	used := uint64(storage.Container.Bytes)
+
+	return &pb.StorageRecord{
+		RecordID:      guid.New().String(),
+		CreateTime:    &timestamp.Timestamp{Seconds: now},
+		StorageSystem: viper.GetString(constants.CfgOpenstackIdentityEndpoint),
+		Site:          util.WrapStr(config.SiteName(config.Storage)),
+		StorageShare:  util.WrapStr("swift"),
+		StorageMedia:  &wrappers.StringValue{Value: "disk"},
+		StorageClass:  util.WrapStr(storage.StoragePolicy),
+		FileCount:     &wrappers.StringValue{Value: strconv.FormatInt(storage.Container.Count, 10)},
+		// DirectoryPath: nil,
+		StartTime:                 startTime,
+		EndTime:                   &timestamp.Timestamp{Seconds: now},
+		ResourceCapacityUsed:      used,
+		LogicalCapacityUsed:       &wrappers.UInt64Value{Value: used},
+		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: used},
+	}
+}
+
+// prepareSwiftAccount returns record of the quota of the object storage account. Containers allocate bytes
+// they use and the account allocates the rest of the quota, so allocated capacity of the account sums up
+// to the quota.
+func prepareSwiftAccount(storage *SwiftAccount) *pb.StorageRecord {
+	now := time.Now().Unix()
+
+	var allocated uint64
+	if storage.Quota > storage.Used {
+		allocated = uint64(storage.Quota - storage.Used)
+	}
+
+	return &pb.StorageRecord{
+		RecordID:                  guid.New().String(),
+		CreateTime:                &timestamp.Timestamp{Seconds: now},
+		StorageSystem:             viper.GetString(constants.CfgOpenstackIdentityEndpoint),
+		Site:                      util.WrapStr(config.SiteName(config.Storage)),
+		StorageShare:              util.WrapStr("swift"),
+		StorageMedia:              &wrappers.StringValue{Value: "disk"},
+		StartTime:                 &timestamp.Timestamp{Seconds: now},
+		EndTime:                   &timestamp.Timestamp{Seconds: now},
+		ResourceCapacityAllocated: &wrappers.UInt64Value{Value: allocated},
 	}
 }
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"github.com/goat-project/goat-os/constants"
+	storageReader "github.com/goat-project/goat-os/resource/storage/reader"
+	"github.com/spf13/viper"
+
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/backups"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+	"github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/shares"
+	manilaSnapshots "github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/snapshots"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Storage Preparer tests", func() {
+	var (
+		preparer *Preparer
+		project  *projects.Project
+	)
+
+	ginkgo.BeforeEach(func() {
+		preparer = &Preparer{userIdentity: map[string]string{"user-id": "user@example.org"}}
+		project = &projects.Project{ID: "project-id", Name: "project"}
+	})
+
+	ginkgo.Describe("resolve owner", func() {
+		ginkgo.Context("when the user is known", func() {
+			ginkgo.It("should use the user and the resolved name", func() {
+				localUser, userIdentity := preparer.owner(project, "user-id")
+
+				gomega.Expect(localUser.Value).To(gomega.Equal("user-id"))
+				gomega.Expect(userIdentity.Value).To(gomega.Equal("user@example.org"))
+			})
+		})
+
+		ginkgo.Context("when the name of the user cannot be resolved", func() {
+			ginkgo.It("should use the user and the project name", func() {
+				localUser, userIdentity := preparer.owner(project, "unknown-id")
+
+				gomega.Expect(localUser.Value).To(gomega.Equal("unknown-id"))
+				gomega.Expect(userIdentity.Value).To(gomega.Equal("project"))
+			})
+		})
+
+		ginkgo.Context("when the user is not set", func() {
+			ginkgo.It("should use the project", func() {
+				localUser, userIdentity := preparer.owner(project, "")
+
+				gomega.Expect(localUser.Value).To(gomega.Equal("project-id"))
+				gomega.Expect(userIdentity.Value).To(gomega.Equal("project"))
+			})
+		})
+	})
+
+	ginkgo.Describe("image owner", func() {
+		ginkgo.It("should return the user from image properties", func() {
+			image := &images.Image{Owner: "project-id", Properties: map[string]interface{}{"user_id": "user-id"}}
+			gomega.Expect(imageOwner(image)).To(gomega.Equal("user-id"))
+		})
+
+		ginkgo.It("should return empty string when the user is not available", func() {
+			gomega.Expect(imageOwner(&images.Image{Owner: "project-id"})).To(gomega.BeEmpty())
+		})
+	})
+
+	ginkgo.Describe("prepare images", func() {
+		ginkgo.It("should use stored size as used and virtual size as allocated capacity", func() {
+			record := prepareImage(&PImage{Project: project, Image: &images.Image{SizeBytes: 100, VirtualSize: 1000,
+				File: "/v2/images/image-id/file"}})
+
+			gomega.Expect(record.FileCount.Value).To(gomega.Equal("1"))
+			gomega.Expect(record.ResourceCapacityUsed).To(gomega.Equal(uint64(100)))
+			gomega.Expect(record.ResourceCapacityAllocated.Value).To(gomega.Equal(uint64(1000)))
+		})
+
+		ginkgo.It("should use stored size as allocated capacity when the virtual size is unknown", func() {
+			record := prepareImage(&PImage{Project: project, Image: &images.Image{SizeBytes: 100}})
+
+			gomega.Expect(record.ResourceCapacityAllocated.Value).To(gomega.Equal(uint64(100)))
+		})
+	})
+
+	ginkgo.Describe("prepare volumes", func() {
+		ginkgo.It("should identify the volume record by ID of the volume", func() {
+			record := prepareVolume(&PVolume{Project: project, Volume: &volumes.Volume{ID: "volume-id", Size: 1}})
+
+			gomega.Expect(record.RecordID).To(gomega.Equal("volume-id"))
+			gomega.Expect(record.StorageShare.Value).To(gomega.Equal("volume"))
+		})
+	})
+
+	ginkgo.Describe("prepare snapshots and backups", func() {
+		ginkgo.It("should create snapshot record with the size of the snapshot", func() {
+			record := prepareSnapshot(&PSnapshot{Project: project, Snapshot: &snapshots.Snapshot{Size: 2}})
+
+			gomega.Expect(record.StorageShare.Value).To(gomega.Equal("snapshot"))
+			gomega.Expect(record.ResourceCapacityUsed).To(gomega.Equal(uint64(2 * 1024 * 1024 * 1024)))
+			gomega.Expect(record.ResourceCapacityAllocated.Value).To(gomega.Equal(uint64(2 * 1024 * 1024 * 1024)))
+		})
+
+		ginkgo.It("should create backup record with the number of backup objects", func() {
+			record := prepareBackup(&PBackup{Project: project, Backup: &backups.Backup{Size: 1, ObjectCount: 21}})
+
+			gomega.Expect(record.StorageShare.Value).To(gomega.Equal("backup"))
+			gomega.Expect(record.FileCount.Value).To(gomega.Equal("21"))
+			gomega.Expect(record.ResourceCapacityUsed).To(gomega.Equal(uint64(1024 * 1024 * 1024)))
+		})
+	})
+
+	ginkgo.Describe("prepare share snapshots and replicas", func() {
+		ginkgo.BeforeEach(func() {
+			viper.Set(constants.CfgShareSnapshotClass, "snapshot-class")
+		})
+
+		ginkgo.AfterEach(func() {
+			viper.Set(constants.CfgShareSnapshotClass, "")
+		})
+
+		ginkgo.It("should create share snapshot record tied to the parent share", func() {
+			record := prepareShareSnapshot(&PShareSnapshot{Project: project,
+				Snapshot: &manilaSnapshots.Snapshot{ShareID: "share-id", Size: 1}})
+
+			gomega.Expect(record.StorageShare.Value).To(gomega.Equal("share-snapshot"))
+			gomega.Expect(record.StorageClass.Value).To(gomega.Equal("snapshot-class"))
+			gomega.Expect(record.DirectoryPath.Value).To(gomega.Equal("share-id"))
+			gomega.Expect(record.ResourceCapacityUsed).To(gomega.Equal(uint64(1024 * 1024 * 1024)))
+		})
+
+		ginkgo.It("should create share replica record with the size of the parent share", func() {
+			record := prepareShareReplica(&PShareReplica{Project: project, Replica: &storageReader.Replica{ID: "replica-id"},
+				Share: &shares.Share{ID: "share-id", Size: 3}})
+
+			gomega.Expect(record.StorageShare.Value).To(gomega.Equal("share-replica"))
+			gomega.Expect(record.StorageClass).To(gomega.BeNil())
+			gomega.Expect(record.DirectoryPath.Value).To(gomega.Equal("share-id"))
+			gomega.Expect(record.ResourceCapacityUsed).To(gomega.Equal(uint64(3 * 1024 * 1024 * 1024)))
+		})
+	})
+
+	ginkgo.Describe("prepare swift containers and accounts", func() {
+		ginkgo.It("should allocate bytes used by the container", func() {
+			record := prepareSwiftContainer(&SwiftContainer{Project: project,
+				Container: &containers.Container{Name: "container", Bytes: 100, Count: 2}})
+
+			gomega.Expect(record.ResourceCapacityUsed).To(gomega.Equal(uint64(100)))
+			gomega.Expect(record.ResourceCapacityAllocated.Value).To(gomega.Equal(uint64(100)))
+		})
+
+		ginkgo.It("should allocate the rest of the quota of the account", func() {
+			record := prepareSwiftAccount(&SwiftAccount{Project: project, Quota: 1000, Used: 300})
+
+			gomega.Expect(record.ResourceCapacityUsed).To(gomega.BeZero())
+			gomega.Expect(record.ResourceCapacityAllocated.Value).To(gomega.Equal(uint64(700)))
+		})
+
+		ginkgo.It("should not allocate anything when the quota of the account is exceeded", func() {
+			record := prepareSwiftAccount(&SwiftAccount{Project: project, Quota: 1000, Used: 1300})
+
+			gomega.Expect(record.ResourceCapacityAllocated.Value).To(gomega.BeZero())
+		})
+	})
+})
@@ -1,21 +1,29 @@
 package storage
 
 import (
+	"math"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/goat-project/goat-os/auth"
 	"github.com/goat-project/goat-os/constants"
 	"github.com/goat-project/goat-os/reader"
 	"github.com/goat-project/goat-os/resource"
+	storageReader "github.com/goat-project/goat-os/resource/storage/reader"
 	"github.com/goat-project/goat-os/util"
 	"github.com/spf13/viper"
 
 	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/backups"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/accounts"
 	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
 	"github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/shares"
+	manilaSnapshots "github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/snapshots"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -25,16 +33,24 @@ const (
 	sharedFileSystem = "sharedFileSystem"
 	manila           = "manila"
 	volume           = "volume"
+	snapshot         = "snapshot"
+	backup           = "backup"
 	swiftContainer   = "swift"
 	all              = "all"
 )
 
+// state of the replica which is the share itself
+const activeReplica = "active"
+
+// header of swift container with creation time of the container
+const timestampHeader = "X-Timestamp"
+
 // Processor to process storage data.
 type Processor struct {
-	computeReader       reader.Reader
-	shareReader         reader.Reader
-	blockStorageReader  reader.Reader
-	objectStorageReader reader.Reader
+	reader             reader.Reader
+	imageReader        reader.Reader
+	shareReader        reader.Reader
+	blockStorageReader reader.Reader
 }
 
 // CreateProcessor creates Processor to manage reading from Openstack.
@@ -45,10 +61,10 @@ func CreateProcessor(r *reader.Reader) *Processor {
 	}
 
 	return &Processor{
-		computeReader:       *r,
-		shareReader:         *r,
-		blockStorageReader:  *r,
-		objectStorageReader: *r,
+		reader:             *r,
+		imageReader:        *r,
+		shareReader:        *r,
+		blockStorageReader: *r,
 	}
 }
 
@@ -58,12 +74,12 @@ func (p *Processor) createReader(osClient *gophercloud.ProviderClient, name stri
 
 	switch name {
 	case image:
-		client, err = auth.CreateComputeV2ServiceClient(osClient)
+		client, err = auth.CreateImageV2ServiceClient(osClient)
 		if err != nil {
-			log.WithFields(log.Fields{"err": err}).Error("unable to create Compute V2 service client")
+			log.WithFields(log.Fields{"err": err}).Error("unable to create Image V2 service client")
 			return
 		}
-		p.computeReader = *reader.CreateReader(client)
+		p.imageReader = *reader.CreateReader(client)
 	case sharedFileSystem, manila:
 		client, err = auth.CreateSharedFileSystemV2ServiceClient(osClient)
 		if err != nil {
@@ -71,26 +87,19 @@ func (p *Processor) createReader(osClient *gophercloud.ProviderClient, name stri
 			return
 		}
 		p.shareReader = *reader.CreateReader(client)
-	case volume:
+	case volume, snapshot, backup:
 		client, err = auth.CreateNewBlockStorageV3ServiceClient(osClient)
 		if err != nil {
 			log.WithFields(log.Fields{"err": err}).Error("unable to create New Block Storage V3 service client")
 			return
 		}
 		p.blockStorageReader = *reader.CreateReader(client)
-	case swiftContainer:
-		client, err = auth.CreateNewObjectStorageV1ServiceClient(osClient)
-		if err != nil {
-			log.WithFields(log.Fields{"err": err}).Error("unable to create Object Storage V1 service client")
-			return
-		}
-		p.objectStorageReader = *reader.CreateReader(client)
 	}
 }
 
 // Reader gets reader.
 func (p *Processor) Reader() *reader.Reader {
-	return &p.computeReader
+	return &p.reader
 }
 
 // Process provides listing of the images with pagination.
@@ -101,10 +110,12 @@ func (p *Processor) Process(project projects.Project, osClient *gophercloud.Prov
 	accounted := viper.GetStringSlice(constants.CfgAccounted)
 
 	if util.Contains(accounted, all) {
-		wg.Add(3)
+		wg.Add(6)
 		go p.processImages(osClient, read, project, wg)
 		go p.processShares(osClient, read, project, wg)
 		go p.processVolumes(osClient, read, project, wg)
+		go p.processSnapshots(osClient, read, project, wg)
+		go p.processBackups(osClient, read, project, wg)
 		go p.processSwiftContainers(osClient, read, project, wg)
 	} else {
 		if util.Contains(accounted, image) {
@@ -121,6 +132,17 @@ func (p *Processor) Process(project projects.Project, osClient *gophercloud.Prov
 			wg.Add(1)
 			go p.processVolumes(osClient, read, project, wg)
 		}
+
+		if util.Contains(accounted, snapshot) {
+			wg.Add(1)
+			go p.processSnapshots(osClient, read, project, wg)
+		}
+
+		if util.Contains(accounted, backup) {
+			wg.Add(1)
+			go p.processBackups(osClient, read, project, wg)
+		}
+
 		if util.Contains(accounted, swiftContainer) {
 			wg.Add(1)
 			go p.processSwiftContainers(osClient, read, project, wg)
@@ -134,7 +156,7 @@ func (p *Processor) processImages(osClient *gophercloud.ProviderClient, read cha
 
 	p.createReader(osClient, image)
 
-	imgs, err := p.computeReader.ListAllImages(project.ID)
+	imgs, err := p.imageReader.ListAllImages(project.ID)
 	if err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("error list images")
 		return
@@ -153,6 +175,11 @@ func (p *Processor) processImages(osClient *gophercloud.ProviderClient, read cha
 	}
 
 	for i := range s {
+		// shared and community images are accounted only to their owner
+		if s[i].Owner != project.ID {
+			continue
+		}
+
 		read <- &PImage{
 			Project: &project,
 			Image:   &s[i],
@@ -184,10 +211,84 @@ func (p *Processor) processShares(osClient *gophercloud.ProviderClient, read cha
 		return
 	}
 
+	owners := extractAttribute(pages, "shares", "user_id")
+
 	for i := range s {
 		read <- &PShare{
 			Project: &project,
 			Share:   &s[i],
+			UserID:  owners[s[i].ID],
+		}
+
+		if s[i].ReplicationType != "" {
+			p.processShareReplicas(read, project, &s[i], owners[s[i].ID])
+		}
+	}
+
+	p.processShareSnapshots(read, project)
+}
+
+func (p *Processor) processShareSnapshots(read chan resource.Resource, project projects.Project) {
+	r, err := p.shareReader.ListAllShareSnapshots(project.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error list share snapshots")
+		return
+	}
+
+	pages, err := r.AllPages() // todo add openstack pagination and wg
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error get share snapshot pages")
+		return
+	}
+
+	s, err := manilaSnapshots.ExtractSnapshots(pages)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error extract share snapshots")
+		return
+	}
+
+	owners := extractAttribute(pages, "snapshots", "user_id")
+
+	for i := range s {
+		read <- &PShareSnapshot{
+			Project:  &project,
+			Snapshot: &s[i],
+			UserID:   owners[s[i].ID],
+		}
+	}
+}
+
+// processShareReplicas sends replicas of the share except the active one, which is the share itself.
+func (p *Processor) processShareReplicas(read chan resource.Resource, project projects.Project, share *shares.Share,
+	user string) {
+	r, err := p.shareReader.ListShareReplicas(share.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "share": share.ID}).Error("error list share replicas")
+		return
+	}
+
+	pages, err := r.AllPages()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "share": share.ID}).Error("error get share replica pages")
+		return
+	}
+
+	s, err := storageReader.ExtractReplicas(pages)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "share": share.ID}).Error("error extract share replicas")
+		return
+	}
+
+	for i := range s {
+		if s[i].ReplicaState == activeReplica {
+			continue
+		}
+
+		read <- &PShareReplica{
+			Project: &project,
+			Replica: &s[i],
+			Share:   share,
+			UserID:  user,
 		}
 	}
 }
@@ -224,13 +325,90 @@ func (p *Processor) processVolumes(osClient *gophercloud.ProviderClient, read ch
 	}
 }
 
+func (p *Processor) processSnapshots(osClient *gophercloud.ProviderClient, read chan resource.Resource,
+	project projects.Project, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	p.createReader(osClient, snapshot)
+
+	r, err := p.blockStorageReader.ListAllSnapshots(project.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error list snapshots")
+		return
+	}
+
+	pages, err := r.AllPages() // todo add openstack pagination and wg
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error get snapshot pages")
+		return
+	}
+
+	rs, err := snapshots.ExtractSnapshots(pages)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error extract snapshots")
+		return
+	}
+
+	owners := extractAttribute(pages, "snapshots", "user_id")
+
+	for i := range rs {
+		read <- &PSnapshot{
+			Project:  &project,
+			Snapshot: &rs[i],
+			UserID:   owners[rs[i].ID],
+		}
+	}
+}
+
+func (p *Processor) processBackups(osClient *gophercloud.ProviderClient, read chan resource.Resource,
+	project projects.Project, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	p.createReader(osClient, backup)
+
+	r, err := p.blockStorageReader.ListAllBackups(project.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error list backups")
+		return
+	}
+
+	pages, err := r.AllPages() // todo add openstack pagination and wg
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error get backup pages")
+		return
+	}
+
+	rs, err := backups.ExtractBackups(pages)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error extract backups")
+		return
+	}
+
+	owners := extractAttribute(pages, "backups", "user_id")
+
+	for i := range rs {
+		read <- &PBackup{
+			Project: &project,
+			Backup:  &rs[i],
+			UserID:  owners[rs[i].ID],
+		}
+	}
+}
+
 func (p *Processor) processSwiftContainers(osClient *gophercloud.ProviderClient, read chan resource.Resource,
 	project projects.Project, wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	p.createReader(osClient, swiftContainer)
+	// every project has its own account, the reader cannot be shared by projects
+	client, err := auth.CreateObjectStorageV1ProjectServiceClient(osClient, project.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("unable to create Object Storage V1 service client")
+		return
+	}
+
+	objectStorageReader := reader.CreateReader(client)
 
-	r, err := p.objectStorageReader.ListAllSwiftContainers()
+	r, err := objectStorageReader.ListAllSwiftContainers()
 	if err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("error list containers")
 		return
@@ -248,10 +426,78 @@ func (p *Processor) processSwiftContainers(osClient *gophercloud.ProviderClient,
 		return
 	}
 
+	var used int64
+
 	for i := range s {
-		read <- &SwiftContainer{
+		container := &SwiftContainer{
 			Project:   &project,
 			Container: &s[i],
 		}
+
+		if err := readContainerMetadata(objectStorageReader, container); err != nil {
+			log.WithFields(log.Fields{"error": err, "container": s[i].Name}).Error("error get container metadata")
+		}
+
+		used += s[i].Bytes
+		read <- container
+	}
+
+	// the quota is shared by all containers of the account, it is reported once for the account
+	if quota := AccountQuota(objectStorageReader); quota > 0 {
+		read <- &SwiftAccount{
+			Project: &project,
+			Quota:   quota,
+			Used:    used,
+		}
+	}
+}
+
+// AccountQuota returns quota of the object storage account in bytes or 0 if the account has no quota.
+func AccountQuota(r *reader.Reader) int64 {
+	rslt, err := r.GetAccount()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error get account")
+		return 0
+	}
+
+	header, err := rslt.(accounts.GetResult).Extract()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error extract account")
+		return 0
 	}
+
+	if header.QuotaBytes != nil {
+		return *header.QuotaBytes
+	}
+
+	return 0
+}
+
+// readContainerMetadata sets creation time and storage policy of the container. They are available only
+// in headers of the container.
+func readContainerMetadata(r *reader.Reader, container *SwiftContainer) error {
+	rslt, err := r.GetContainer(container.Container.Name)
+	if err != nil {
+		return err
+	}
+
+	getResult := rslt.(containers.GetResult)
+
+	header, err := getResult.Extract()
+	if err != nil {
+		return err
+	}
+
+	container.StoragePolicy = header.StoragePolicy
+
+	// X-Timestamp is unix time with fraction of a second
+	timestamp, err := strconv.ParseFloat(getResult.Header.Get(timestampHeader), 64)
+	if err != nil {
+		return err
+	}
+
+	sec, dec := math.Modf(timestamp)
+	container.CreatedAt = time.Unix(int64(sec), int64(dec*1e9))
+
+	return nil
 }
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/goat-project/goat-os/reader"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/backups"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Storage Processor tests", func() {
+	var (
+		server *httptest.Server
+		r      *reader.Reader
+	)
+
+	ginkgo.BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch req.URL.Path {
+			case "/container":
+				w.Header().Set("X-Timestamp", "1610000000.50000")
+				w.Header().Set("X-Storage-Policy", "gold")
+				w.WriteHeader(http.StatusNoContent)
+			case "/":
+				w.Header().Set("X-Account-Meta-Quota-Bytes", "1000")
+				w.WriteHeader(http.StatusNoContent)
+			case "/snapshots/detail":
+				gomega.Expect(req.URL.Query().Get("project_id")).To(gomega.Equal("project-id"))
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"snapshots": [{"id": "snapshot-id", "size": 2, "user_id": "user-id",
+					"created_at": "2021-01-02T03:04:05.000000"}]}`)
+			case "/backups/detail":
+				gomega.Expect(req.URL.Query().Get("project_id")).To(gomega.Equal("project-id"))
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"backups": [{"id": "backup-id", "size": 3, "user_id": "user-id",
+					"created_at": "2021-01-02T03:04:05.000000"}]}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		r = reader.CreateReader(&gophercloud.ServiceClient{
+			ProviderClient: &gophercloud.ProviderClient{TokenID: "token"},
+			Endpoint:       server.URL + "/",
+		})
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+	})
+
+	ginkgo.Describe("read container metadata", func() {
+		ginkgo.It("should set creation time and storage policy of the container", func() {
+			container := &SwiftContainer{Container: &containers.Container{Name: "container"}}
+
+			gomega.Expect(readContainerMetadata(r, container)).To(gomega.Succeed())
+			gomega.Expect(container.StoragePolicy).To(gomega.Equal("gold"))
+			gomega.Expect(container.CreatedAt).To(gomega.Equal(time.Unix(1610000000, 500000000)))
+		})
+
+		ginkgo.It("should return error when the container does not exist", func() {
+			container := &SwiftContainer{Container: &containers.Container{Name: "missing"}}
+
+			gomega.Expect(readContainerMetadata(r, container)).NotTo(gomega.Succeed())
+			gomega.Expect(container.CreatedAt.IsZero()).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Describe("read snapshots and backups", func() {
+		created := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		ginkgo.It("should read size, creation time and owner of snapshots", func() {
+			pager, err := r.ListAllSnapshots("project-id")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			pages, err := pager.AllPages()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			s, err := snapshots.ExtractSnapshots(pages)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(s).To(gomega.HaveLen(1))
+			gomega.Expect(s[0].Size).To(gomega.Equal(2))
+			gomega.Expect(s[0].CreatedAt).To(gomega.Equal(created))
+			gomega.Expect(extractAttribute(pages, "snapshots", "user_id")).To(gomega.HaveKeyWithValue(
+				"snapshot-id", "user-id"))
+		})
+
+		ginkgo.It("should read size, creation time and owner of backups", func() {
+			pager, err := r.ListAllBackups("project-id")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			pages, err := pager.AllPages()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			b, err := backups.ExtractBackups(pages)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(b).To(gomega.HaveLen(1))
+			gomega.Expect(b[0].Size).To(gomega.Equal(3))
+			gomega.Expect(b[0].CreatedAt).To(gomega.Equal(created))
+			gomega.Expect(extractAttribute(pages, "backups", "user_id")).To(gomega.HaveKeyWithValue(
+				"backup-id", "user-id"))
+		})
+	})
+
+	ginkgo.Describe("account quota", func() {
+		ginkgo.It("should return quota of the account", func() {
+			gomega.Expect(AccountQuota(r)).To(gomega.Equal(int64(1000)))
+		})
+	})
+})
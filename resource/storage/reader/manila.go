@@ -0,0 +1,82 @@
+package reader
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/pagination"
+)
+
+// header required by share replica requests until the API is not experimental (microversion 2.56)
+const experimentalHeader = "X-OpenStack-Manila-API-Experimental"
+
+// ShareReplica structure for a Reader which read an array of replicas of a share.
+type ShareReplica struct {
+	ShareID string
+}
+
+// Replica represents a replica of a share. The active replica is the share itself.
+type Replica struct {
+	ID               string    `json:"id"`
+	ShareID          string    `json:"share_id"`
+	Status           string    `json:"status"`
+	ReplicaState     string    `json:"replica_state"`
+	AvailabilityZone string    `json:"availability_zone"`
+	Host             string    `json:"host"`
+	CreatedAt        time.Time `json:"-"`
+}
+
+// UnmarshalJSON parses the creation time of the replica.
+func (r *Replica) UnmarshalJSON(b []byte) error {
+	type tmp Replica
+	var s struct {
+		tmp
+		CreatedAt gophercloud.JSONRFC3339MilliNoZ `json:"created_at"`
+	}
+
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	*r = Replica(s.tmp)
+	r.CreatedAt = time.Time(s.CreatedAt)
+
+	return nil
+}
+
+// ReplicaPage is a single page of share replicas.
+type ReplicaPage struct {
+	pagination.SinglePageBase
+}
+
+// IsEmpty returns true if the page contains no replicas.
+func (p ReplicaPage) IsEmpty() (bool, error) {
+	replicas, err := ExtractReplicas(p)
+	return len(replicas) == 0, err
+}
+
+// ExtractReplicas extracts share replicas from the page.
+func ExtractReplicas(p pagination.Page) ([]Replica, error) {
+	var s struct {
+		Replicas []Replica `json:"share_replicas"`
+	}
+
+	err := (p.(ReplicaPage)).ExtractInto(&s)
+
+	return s.Replicas, err
+}
+
+// ReadResources reads an array of replicas of the share.
+func (sr *ShareReplica) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	query := url.Values{"share_id": {sr.ShareID}}
+	u := client.ServiceURL("share-replicas", "detail") + "?" + query.Encode()
+
+	pager := pagination.NewPager(client, u, func(r pagination.PageResult) pagination.Page {
+		return ReplicaPage{pagination.SinglePageBase(r)}
+	})
+	pager.Headers = map[string]string{experimentalHeader: "true"}
+
+	return pager
+}
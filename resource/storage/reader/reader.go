@@ -1,14 +1,36 @@
 package reader
 
 import (
+	"github.com/goat-project/goat-os/result"
+
 	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/backups"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/accounts"
 	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
 	"github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/shares"
+	manilaSnapshots "github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/snapshots"
 	"github.com/gophercloud/gophercloud/pagination"
 )
 
+// visibility of images which lists images of all visibilities
+const visibilityAll images.ImageVisibility = "all"
+
+// backupListDetailOpts are options of the detailed list of backups, backups.ListDetailOpts cannot filter
+// backups by project.
+type backupListDetailOpts struct {
+	AllTenants bool   `q:"all_tenants"`
+	ProjectID  string `q:"project_id"`
+}
+
+// ToBackupListDetailQuery formats backupListDetailOpts into a query string.
+func (opts backupListDetailOpts) ToBackupListDetailQuery() (string, error) {
+	q, err := gophercloud.BuildQueryString(opts)
+	return q.String(), err
+}
+
 // Image structure for a Reader which read an array of images.
 type Image struct {
 	ProjectID string
@@ -19,18 +41,43 @@ type Share struct {
 	ProjectID string
 }
 
+// ShareSnapshot structure for a Reader which read an array of share snapshots.
+type ShareSnapshot struct {
+	ProjectID string
+}
+
 // Volume structure for a Reader which read an array of volumes.
 type Volume struct {
 	ProjectID string
 }
 
+// Snapshot structure for a Reader which read an array of volume snapshots.
+type Snapshot struct {
+	ProjectID string
+}
+
+// Backup structure for a Reader which read an array of volume backups.
+type Backup struct {
+	ProjectID string
+}
+
 // Swift structure for a Reader which read an array of swift containers.
 type Swift struct {
 }
 
+// Container structure for a Reader which read metadata of a swift container.
+type Container struct {
+	Name string
+}
+
+// Account structure for a Reader which read metadata of the object storage account.
+type Account struct {
+}
+
 // ReadResources reads an array of storages.
 func (i *Image) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
-	return images.List(client, images.ListOpts{Owner: i.ProjectID})
+	// community images are listed only with visibility all
+	return images.List(client, images.ListOpts{Owner: i.ProjectID, Visibility: visibilityAll})
 }
 
 // ReadResources reads an array of storages.
@@ -38,6 +85,11 @@ func (s *Share) ReadResources(client *gophercloud.ServiceClient) pagination.Page
 	return shares.ListDetail(client, shares.ListOpts{ProjectID: s.ProjectID})
 }
 
+// ReadResources reads an array of storages.
+func (ss *ShareSnapshot) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return manilaSnapshots.ListDetail(client, manilaSnapshots.ListOpts{AllTenants: true, ProjectID: ss.ProjectID})
+}
+
 // ReadResources reads an array of storages.
 func (v *Volume) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
 	return volumes.List(client, volumes.ListOpts{TenantID: v.ProjectID})
@@ -47,3 +99,34 @@ func (v *Volume) ReadResources(client *gophercloud.ServiceClient) pagination.Pag
 func (s *Swift) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
 	return containers.List(client, containers.ListOpts{Full: true})
 }
+
+// ReadResources reads an array of storages. Snapshots are listed in detail since only the detailed list
+// contains owners of snapshots.
+func (s *Snapshot) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	query, err := snapshots.ListOpts{AllTenants: true, TenantID: s.ProjectID}.ToSnapshotListQuery()
+	if err != nil {
+		return pagination.Pager{Err: err}
+	}
+
+	url := client.ServiceURL("snapshots", "detail") + query
+
+	return pagination.NewPager(client, url, func(r pagination.PageResult) pagination.Page {
+		return snapshots.SnapshotPage{LinkedPageBase: pagination.LinkedPageBase{PageResult: r}}
+	})
+}
+
+// ReadResources reads an array of storages. Backups are listed in detail since the list contains only names
+// of backups.
+func (b *Backup) ReadResources(client *gophercloud.ServiceClient) pagination.Pager {
+	return backups.ListDetail(client, backupListDetailOpts{AllTenants: true, ProjectID: b.ProjectID})
+}
+
+// ReadResource reads metadata of the swift container.
+func (c *Container) ReadResource(client *gophercloud.ServiceClient) result.Result {
+	return containers.Get(client, c.Name, nil)
+}
+
+// ReadResource reads metadata of the object storage account.
+func (a *Account) ReadResource(client *gophercloud.ServiceClient) result.Result {
+	return accounts.Get(client, nil)
+}
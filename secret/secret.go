@@ -0,0 +1,103 @@
+package secret
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// prefixes of indirect secret values
+const (
+	filePrefix = "file://"
+	envPrefix  = "env:"
+	execPrefix = "exec:"
+)
+
+// Mask replaces secret value in the log output.
+const Mask = "********"
+
+// Resolve returns secret referenced by value. A value prefixed with "file://" is read from the file,
+// a value prefixed with "env:" is read from the environment variable and a value prefixed with "exec:"
+// is read from the standard output of the command. The command is split into arguments at white space
+// without any shell quoting, a command with arguments containing spaces has to be wrapped in a script.
+// Other values are returned unchanged.
+func Resolve(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, filePrefix):
+		return fromFile(strings.TrimPrefix(value, filePrefix))
+	case strings.HasPrefix(value, envPrefix):
+		return fromEnv(strings.TrimPrefix(value, envPrefix))
+	case strings.HasPrefix(value, execPrefix):
+		return fromExec(strings.TrimPrefix(value, execPrefix))
+	default:
+		return value, nil
+	}
+}
+
+// IsReference returns whether the value refers to a secret stored in a file, an environment variable
+// or a command output.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, filePrefix) || strings.HasPrefix(value, envPrefix) ||
+		strings.HasPrefix(value, execPrefix)
+}
+
+// ResolveAll replaces values of the given configuration keys which refer to a secret with the secret.
+// Values of other keys are never resolved.
+func ResolveAll(v *viper.Viper, keys []string) error {
+	for _, key := range keys {
+		value, ok := v.Get(key).(string)
+		if !ok || !IsReference(value) {
+			continue
+		}
+
+		resolved, err := Resolve(value)
+		if err != nil {
+			return fmt.Errorf("unable to resolve secret of %s: %v", key, err)
+		}
+
+		v.Set(key, resolved)
+	}
+
+	return nil
+}
+
+func fromFile(filePath string) (string, error) {
+	if filePath == "" {
+		return "", fmt.Errorf("empty secret file path")
+	}
+
+	content, err := ioutil.ReadFile(path.Clean(filePath))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+func fromEnv(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+
+	return value, nil
+}
+
+func fromExec(command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", fmt.Errorf("empty secret command")
+	}
+
+	out, err := exec.Command(args[0], args[1:]...).Output() // nolint: gosec
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
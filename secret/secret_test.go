@@ -0,0 +1,108 @@
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Secret tests", func() {
+	ginkgo.Describe("resolve secret", func() {
+		ginkgo.Context("when value is plain text", func() {
+			ginkgo.It("should return value unchanged", func() {
+				gomega.Expect(Resolve("plain-password")).To(gomega.Equal("plain-password"))
+			})
+		})
+
+		ginkgo.Context("when value refers to a file", func() {
+			var dir string
+
+			ginkgo.BeforeEach(func() {
+				var err error
+				dir, err = ioutil.TempDir("", "goat-os-secret")
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+
+			ginkgo.AfterEach(func() {
+				_ = os.RemoveAll(dir)
+			})
+
+			ginkgo.It("should return content of the file without trailing new line", func() {
+				file := filepath.Join(dir, "os_password")
+				gomega.Expect(ioutil.WriteFile(file, []byte("file-password\n"), 0600)).To(gomega.Succeed())
+
+				gomega.Expect(Resolve("file://" + file)).To(gomega.Equal("file-password"))
+			})
+
+			ginkgo.It("should return error when the file does not exist", func() {
+				_, err := Resolve("file://" + filepath.Join(dir, "missing"))
+
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when value refers to an environment variable", func() {
+			ginkgo.It("should return value of the variable", func() {
+				gomega.Expect(os.Setenv("GOAT_OS_TEST_SECRET", "env-password")).To(gomega.Succeed())
+				defer os.Unsetenv("GOAT_OS_TEST_SECRET") // nolint: errcheck
+
+				gomega.Expect(Resolve("env:GOAT_OS_TEST_SECRET")).To(gomega.Equal("env-password"))
+			})
+
+			ginkgo.It("should return error when the variable is not set", func() {
+				_, err := Resolve("env:GOAT_OS_TEST_SECRET_MISSING")
+
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		ginkgo.Context("when value refers to a command", func() {
+			ginkgo.It("should return output of the command", func() {
+				gomega.Expect(Resolve("exec:echo exec-password")).To(gomega.Equal("exec-password"))
+			})
+
+			ginkgo.It("should return error when the command is empty", func() {
+				_, err := Resolve("exec:")
+
+				gomega.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	})
+
+	ginkgo.Describe("resolve all secrets", func() {
+		ginkgo.BeforeEach(func() {
+			gomega.Expect(os.Setenv("GOAT_OS_TEST_SECRET", "env-password")).To(gomega.Succeed())
+		})
+
+		ginkgo.AfterEach(func() {
+			_ = os.Unsetenv("GOAT_OS_TEST_SECRET")
+		})
+
+		ginkgo.It("should resolve references of the given keys only", func() {
+			v := viper.New()
+			v.Set("auth-options.password", "env:GOAT_OS_TEST_SECRET")
+			v.Set("auth-options.token-id", "")
+			v.Set("storage.swift-reseller-prefix", "exec:echo AUTH_")
+			v.Set("auth-options.username", "env:GOAT_OS_TEST_SECRET")
+
+			gomega.Expect(ResolveAll(v, []string{"auth-options.password", "auth-options.token-id"})).To(
+				gomega.Succeed())
+			gomega.Expect(v.GetString("auth-options.password")).To(gomega.Equal("env-password"))
+			gomega.Expect(v.GetString("auth-options.token-id")).To(gomega.BeEmpty())
+			gomega.Expect(v.GetString("storage.swift-reseller-prefix")).To(gomega.Equal("exec:echo AUTH_"))
+			gomega.Expect(v.GetString("auth-options.username")).To(gomega.Equal("env:GOAT_OS_TEST_SECRET"))
+		})
+
+		ginkgo.It("should return error when a reference cannot be resolved", func() {
+			v := viper.New()
+			v.Set("auth-options.token-id", "env:GOAT_OS_TEST_SECRET_MISSING")
+
+			gomega.Expect(ResolveAll(v, []string{"auth-options.token-id"})).NotTo(gomega.Succeed())
+		})
+	})
+})
@@ -0,0 +1,55 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("State tests", func() {
+	var dir string
+
+	ginkgo.BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "goat-os-state")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.AfterEach(func() {
+		gomega.Expect(os.RemoveAll(dir)).To(gomega.Succeed())
+	})
+
+	ginkgo.Context("when the state file does not exist", func() {
+		ginkgo.It("should leave the state unchanged", func() {
+			s := map[string]int{"a": 1}
+
+			gomega.Expect(Load(filepath.Join(dir, "missing.json"), &s)).To(gomega.Succeed())
+			gomega.Expect(s).To(gomega.Equal(map[string]int{"a": 1}))
+		})
+	})
+
+	ginkgo.Context("when the state is saved", func() {
+		ginkgo.It("should be loaded back", func() {
+			path := filepath.Join(dir, "state.json")
+
+			gomega.Expect(Save(path, map[string]int{"a": 1, "b": 2})).To(gomega.Succeed())
+
+			var s map[string]int
+			gomega.Expect(Load(path, &s)).To(gomega.Succeed())
+			gomega.Expect(s).To(gomega.Equal(map[string]int{"a": 1, "b": 2}))
+		})
+	})
+
+	ginkgo.Context("when the state file is corrupted", func() {
+		ginkgo.It("should return an error", func() {
+			path := filepath.Join(dir, "state.json")
+			gomega.Expect(ioutil.WriteFile(path, []byte("{"), 0600)).To(gomega.Succeed())
+
+			var s map[string]int
+			gomega.Expect(Load(path, &s)).NotTo(gomega.Succeed())
+		})
+	})
+})
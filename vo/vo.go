@@ -0,0 +1,336 @@
+package vo
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/reader"
+	"github.com/goat-project/goat-os/util"
+
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/domains"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/roles"
+
+	"github.com/spf13/viper"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// sources of virtual organization names
+const (
+	sourceProject  = "project"
+	sourceStatic   = "static"
+	sourceProperty = "property"
+	sourceTag      = "tag"
+	sourceTemplate = "template"
+)
+
+// default settings
+const (
+	defaultProperty     = "vo"
+	defaultTagPrefix    = "vo:"
+	defaultTemplate     = "{{.Project.Name}}"
+	defaultFqanTemplate = "/{{.VO}}/Role={{.Role}}/Capability=NULL"
+	nullRole            = "NULL"
+	legacyFqanFormat    = "/%s/Role=NULL/Capability=NULL"
+)
+
+// Group of a user in accounting records - virtual organization, role and FQAN.
+type Group struct {
+	VO   string
+	Role string
+	Fqan string
+}
+
+// Entry of the static table mapping a project given by id or name to a virtual organization
+// and optionally a role.
+type Entry struct {
+	Project string `mapstructure:"project"`
+	VO      string `mapstructure:"vo"`
+	Role    string `mapstructure:"role"`
+}
+
+// data available in templates
+type templateData struct {
+	Project projects.Project
+	Domain  string
+	User    string
+	Roles   []string
+	VO      string
+	Role    string
+}
+
+// Mapper maps Keystone projects and roles of users in the projects to groups.
+type Mapper struct {
+	enabled      bool
+	reader       reader.Reader
+	source       string
+	table        []Entry
+	property     string
+	tagPrefix    string
+	template     *template.Template
+	fqanTemplate *template.Template
+	roles        []string
+	withDomain   bool
+	withRoles    bool
+
+	mu          sync.Mutex
+	domains     map[string]string
+	roleNames   map[string]string
+	assignments map[string]map[string][]string
+}
+
+// CreateMapper creates Mapper according to configuration. Projects are mapped to virtual organizations
+// by project name by default.
+func CreateMapper(r *reader.Reader) *Mapper {
+	if r == nil {
+		log.WithFields(log.Fields{}).Error("error create vo mapper when reader is nil")
+		return nil
+	}
+
+	m := &Mapper{
+		enabled:     viper.GetBool(constants.CfgVOEnabled),
+		reader:      *r,
+		source:      stringOrDefault(constants.CfgVOSource, sourceProject),
+		property:    stringOrDefault(constants.CfgVOProperty, defaultProperty),
+		tagPrefix:   stringOrDefault(constants.CfgVOTagPrefix, defaultTagPrefix),
+		roles:       viper.GetStringSlice(constants.CfgVORoles),
+		domains:     make(map[string]string),
+		assignments: make(map[string]map[string][]string),
+	}
+
+	if err := viper.UnmarshalKey(constants.CfgVOTable, &m.table); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error read vo table")
+	}
+
+	text := stringOrDefault(constants.CfgVOTemplate, defaultTemplate)
+	fqanText := stringOrDefault(constants.CfgVOFqanTemplate, defaultFqanTemplate)
+
+	m.template = parse("vo", text)
+	m.fqanTemplate = parse("fqan", fqanText)
+
+	if m.source != sourceTemplate {
+		text = ""
+	}
+
+	// domains and role assignments are read only when they are used
+	m.withDomain = strings.Contains(text+fqanText, ".Domain")
+	m.withRoles = len(m.roles) > 0 || strings.Contains(text+fqanText, ".Roles")
+
+	return m
+}
+
+// Enabled returns whether projects are mapped to virtual organizations. When the mapping is not enabled,
+// records keep their original groups and FQANs, see LegacyFqan.
+func (m *Mapper) Enabled() bool {
+	return m != nil && m.enabled
+}
+
+// LegacyFqan returns FQAN of records used when the mapping is not enabled, the group is id of a project
+// or a domain.
+func LegacyFqan(group string) string {
+	return fmt.Sprintf(legacyFqanFormat, group)
+}
+
+// Map returns group of the user in the project. The user is empty when a resource is not owned by any user.
+// A project without a virtual organization in the configured source is mapped to the project name.
+func (m *Mapper) Map(project *projects.Project, userID string) Group {
+	if project == nil {
+		return Group{}
+	}
+
+	data := templateData{Project: *project, User: userID}
+
+	if m.withDomain {
+		data.Domain = m.domainName(project.DomainID)
+	}
+
+	if m.withRoles && userID != "" {
+		data.Roles = m.userRoles(project.ID, userID)
+	}
+
+	vo, role := m.lookup(project, data)
+	if vo == "" {
+		vo = project.Name
+	}
+
+	if role == "" {
+		role = m.preferredRole(data.Roles)
+	}
+
+	data.VO = vo
+	data.Role = role
+
+	return Group{VO: vo, Role: role, Fqan: render(m.fqanTemplate, data)}
+}
+
+func (m *Mapper) lookup(project *projects.Project, data templateData) (string, string) {
+	switch m.source {
+	case sourceStatic:
+		for _, entry := range m.table {
+			if entry.Project == project.ID || entry.Project == project.Name {
+				return entry.VO, entry.Role
+			}
+		}
+	case sourceProperty:
+		if vo, ok := project.Extra[m.property].(string); ok {
+			return vo, ""
+		}
+	case sourceTag:
+		for _, tag := range project.Tags {
+			if strings.HasPrefix(tag, m.tagPrefix) {
+				return strings.TrimPrefix(tag, m.tagPrefix), ""
+			}
+		}
+	case sourceTemplate:
+		return render(m.template, data), ""
+	}
+
+	return "", ""
+}
+
+// preferredRole returns the first configured role which the user has or NULL role.
+func (m *Mapper) preferredRole(userRoles []string) string {
+	for _, role := range m.roles {
+		if util.Contains(userRoles, role) {
+			return role
+		}
+	}
+
+	return nullRole
+}
+
+func (m *Mapper) domainName(id string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if name, ok := m.domains[id]; ok {
+		return name
+	}
+
+	m.domains[id] = id // domain id is used when the domain cannot be read
+
+	rslt, err := m.reader.GetDomain(id)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "id": id}).Error("error get domain")
+		return id
+	}
+
+	domain, err := rslt.(domains.GetResult).Extract()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "id": id}).Error("error extract domain")
+		return id
+	}
+
+	m.domains[id] = domain.Name
+
+	return domain.Name
+}
+
+// userRoles returns names of roles of the user in the project, role assignments are read once per project.
+func (m *Mapper) userRoles(projectID, userID string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.roleNames == nil {
+		m.roleNames = m.listRoleNames()
+	}
+
+	users, ok := m.assignments[projectID]
+	if !ok {
+		users = m.listAssignments(projectID)
+		m.assignments[projectID] = users
+	}
+
+	return users[userID]
+}
+
+func (m *Mapper) listRoleNames() map[string]string {
+	names := make(map[string]string)
+
+	pager, err := m.reader.ListRoles()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error list roles")
+		return names
+	}
+
+	pages, err := pager.AllPages()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error get role pages")
+		return names
+	}
+
+	rls, err := roles.ExtractRoles(pages)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("error extract roles")
+		return names
+	}
+
+	for _, role := range rls {
+		names[role.ID] = role.Name
+	}
+
+	return names
+}
+
+func (m *Mapper) listAssignments(projectID string) map[string][]string {
+	users := make(map[string][]string)
+
+	pager, err := m.reader.ListRoleAssignments(projectID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": projectID}).Error("error list role assignments")
+		return users
+	}
+
+	pages, err := pager.AllPages()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": projectID}).Error("error get role assignment pages")
+		return users
+	}
+
+	assignments, err := roles.ExtractRoleAssignments(pages)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "project": projectID}).Error("error extract role assignments")
+		return users
+	}
+
+	for _, assignment := range assignments {
+		if name, ok := m.roleNames[assignment.Role.ID]; ok && assignment.User.ID != "" {
+			users[assignment.User.ID] = append(users[assignment.User.ID], name)
+		}
+	}
+
+	return users
+}
+
+func parse(name, text string) *template.Template {
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "template": text}).Fatal("error parse " + name + " template")
+	}
+
+	return t
+}
+
+func render(t *template.Template, data templateData) string {
+	var b bytes.Buffer
+
+	if err := t.Execute(&b, data); err != nil {
+		log.WithFields(log.Fields{"error": err, "template": t.Name()}).Error("error execute template")
+		return ""
+	}
+
+	return b.String()
+}
+
+func stringOrDefault(key, value string) string {
+	if v := viper.GetString(key); v != "" {
+		return v
+	}
+
+	return value
+}
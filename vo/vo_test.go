@@ -0,0 +1,115 @@
+package vo
+
+import (
+	"github.com/goat-project/goat-os/constants"
+	"github.com/goat-project/goat-os/reader"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"github.com/spf13/viper"
+)
+
+var _ = ginkgo.Describe("VO Mapper tests", func() {
+	var (
+		mapper  *Mapper
+		project = &projects.Project{
+			ID:    "project-id",
+			Name:  "project-name",
+			Tags:  []string{"env:prod", "vo:biomed"},
+			Extra: map[string]interface{}{"vo": "vo.example.eu"},
+		}
+	)
+
+	ginkgo.JustBeforeEach(func() {
+		mapper = CreateMapper(reader.CreateReader(&gophercloud.ServiceClient{}))
+	})
+
+	ginkgo.AfterEach(func() {
+		viper.Reset()
+	})
+
+	ginkgo.Context("when the mapping is not enabled", func() {
+		ginkgo.It("should not be enabled", func() {
+			gomega.Expect(mapper.Enabled()).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should return the legacy fqan of the group", func() {
+			gomega.Expect(LegacyFqan("project-id")).To(gomega.Equal("/project-id/Role=NULL/Capability=NULL"))
+		})
+	})
+
+	ginkgo.Context("when the mapping is enabled", func() {
+		ginkgo.BeforeEach(func() {
+			viper.Set(constants.CfgVOEnabled, true)
+		})
+
+		ginkgo.It("should be enabled", func() {
+			gomega.Expect(mapper.Enabled()).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Context("when no source is configured", func() {
+		ginkgo.It("should map the project to vo of the project name", func() {
+			gomega.Expect(mapper.Map(project, "user")).To(gomega.Equal(Group{
+				VO: "project-name", Role: "NULL", Fqan: "/project-name/Role=NULL/Capability=NULL",
+			}))
+		})
+	})
+
+	ginkgo.Context("when the static table is configured", func() {
+		ginkgo.BeforeEach(func() {
+			viper.Set(constants.CfgVOSource, "static")
+			viper.Set(constants.CfgVOTable, []map[string]string{
+				{"project": "other", "vo": "other-vo"},
+				{"project": "project-id", "vo": "fedcloud.egi.eu", "role": "VMManager"},
+			})
+		})
+
+		ginkgo.It("should map the project to the vo and role of the table", func() {
+			gomega.Expect(mapper.Map(project, "user").Fqan).To(gomega.Equal(
+				"/fedcloud.egi.eu/Role=VMManager/Capability=NULL"))
+		})
+
+		ginkgo.It("should fall back to the project name for other projects", func() {
+			gomega.Expect(mapper.Map(&projects.Project{ID: "x", Name: "unknown"}, "").VO).To(gomega.Equal("unknown"))
+		})
+	})
+
+	ginkgo.Context("when the project property is configured", func() {
+		ginkgo.BeforeEach(func() {
+			viper.Set(constants.CfgVOSource, "property")
+		})
+
+		ginkgo.It("should map the project to the vo of the property", func() {
+			gomega.Expect(mapper.Map(project, "user").VO).To(gomega.Equal("vo.example.eu"))
+		})
+	})
+
+	ginkgo.Context("when the project tag is configured", func() {
+		ginkgo.BeforeEach(func() {
+			viper.Set(constants.CfgVOSource, "tag")
+		})
+
+		ginkgo.It("should map the project to the vo of the tag", func() {
+			gomega.Expect(mapper.Map(project, "user").VO).To(gomega.Equal("biomed"))
+		})
+	})
+
+	ginkgo.Context("when the template is configured", func() {
+		ginkgo.BeforeEach(func() {
+			viper.Set(constants.CfgVOSource, "template")
+			viper.Set(constants.CfgVOTemplate, "vo.{{.Project.Name}}")
+			viper.Set(constants.CfgVOFqanTemplate, "/{{.VO}}/{{.Project.ID}}")
+		})
+
+		ginkgo.It("should map the project to the vo and fqan of the templates", func() {
+			gomega.Expect(mapper.Map(project, "user")).To(gomega.Equal(Group{
+				VO: "vo.project-name", Role: "NULL", Fqan: "/vo.project-name/project-id",
+			}))
+		})
+	})
+})